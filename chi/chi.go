@@ -0,0 +1,154 @@
+/*
+Package chi has functions to use with chi (https://go-chi.io).
+It builds on the nethttp package and adds the chi route pattern, like /users/{id}, as a ctxerr field
+so errors are grouped by route instead of by raw path.
+
+	import ctxchi "github.com/mvndaai/ctxerrhelper/chi"
+
+	func main() {
+		conf := ctxchi.Config{ShowMessage: config.ShowMessage, ShowFields: config.ShowFields}
+
+		r := chi.NewRouter()
+		r.Use(middleware.RequestID, ctxchi.RequestFields, conf.Recover)
+		r.NotFound(conf.NotFound)
+		r.MethodNotAllowed(conf.MethodNotAllowed)
+		r.Method(http.MethodGet, "/users/{id}", conf.Handler(func(w http.ResponseWriter, r *http.Request) error {
+			...
+		}))
+		...
+	}
+*/
+package chi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+const (
+	// CodePanic is the ctxerr code of errors created by Recover
+	CodePanic = ctxnethttp.CodePanic
+	// CodeNotFound is the ctxerr code of errors written by NotFound
+	CodeNotFound = "not_found"
+	// CodeMethodNotAllowed is the ctxerr code of errors written by MethodNotAllowed
+	CodeMethodNotAllowed = "method_not_allowed"
+)
+
+// Config configures how errors are written
+type Config struct {
+	// ShowMessage adds the error message to the response
+	ShowMessage bool
+	// ShowFields adds the ctxerr fields to the response
+	ShowFields bool
+	// LogError is a way to log an error not using ctxerr.Handle to avoid circular errors
+	LogError func(error)
+}
+
+func (c Config) nethttp() ctxnethttp.Config {
+	return ctxnethttp.Config{ShowMessage: c.ShowMessage, ShowFields: c.ShowFields, LogError: c.LogError}
+}
+
+// Handler adapts f to an http.Handler that sets the route pattern field and writes any returned error
+// using the nethttp package.
+func (c Config) Handler(f ctxnethttp.HandlerFunc) http.Handler {
+	return RouteFields(c.nethttp().Handler(f))
+}
+
+// WriteError calls ctxerr.Handle and writes the error using the nethttp package
+func (c Config) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	c.nethttp().WriteError(w, r, err)
+}
+
+// NotFound can be used with chi's NotFound to write a ctxerr 404 response with the code CodeNotFound
+func (c Config) NotFound(w http.ResponseWriter, r *http.Request) {
+	c.WriteError(w, r, ctxerr.NewHTTP(r.Context(), CodeNotFound, "", http.StatusNotFound, "not found"))
+}
+
+// MethodNotAllowed can be used with chi's MethodNotAllowed to write a ctxerr 405 response with the code CodeMethodNotAllowed.
+// chi does not set the Allow header when a custom handler is used so it is set from the methods the router matches.
+func (c Config) MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	for _, m := range allowedMethods(r) {
+		w.Header().Add("Allow", m)
+	}
+	ctx := withRoute(r).Context()
+	c.WriteError(w, r, ctxerr.NewHTTP(ctx, CodeMethodNotAllowed, "", http.StatusMethodNotAllowed, "method not allowed"))
+}
+
+// allowedMethods returns the methods the router has a route for at the request path
+func allowedMethods(r *http.Request) []string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return nil
+	}
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	var methods []string
+	for _, m := range []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+	} {
+		if rctx.Routes.Match(chi.NewRouteContext(), m, path) {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// RequestFields is the nethttp RequestFields middleware that also uses the request ID
+// from chi's RequestID middleware when it runs first.
+func RequestFields(next http.Handler) http.Handler {
+	return ctxnethttp.RequestFields(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+			r = r.WithContext(ctxerr.SetField(r.Context(), ctxerrfields.FieldKeyRequestID, requestID))
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// RouteFields is a middleware that sets the chi route pattern as a ctxerr field.
+// chi only knows the full pattern after routing, so use it with r.With or r.Group instead of r.Use on the root router.
+func RouteFields(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, withRoute(r))
+	})
+}
+
+// withRoute sets the route pattern field if chi has matched a route
+func withRoute(r *http.Request) *http.Request {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return r
+	}
+	route := rctx.RoutePattern()
+	if route == "" {
+		return r
+	}
+	return r.WithContext(ctxerr.SetField(r.Context(), ctxerrfields.FieldKeyHTTPRoute, route))
+}
+
+// Recover is a middleware that converts a panic with ctxnethttp.PanicError and adds the route pattern as a field.
+// It can be used with r.Use because the route pattern is read after the panic when routing has finished.
+func (c Config) Recover(next http.Handler) http.Handler {
+	return c.nethttp().Handler(func(w http.ResponseWriter, r *http.Request) (err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			err = ctxnethttp.PanicError(withRoute(r).Context(), rec)
+		}()
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
@@ -0,0 +1,188 @@
+package chi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxchi "github.com/mvndaai/ctxerrhelper/chi"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) ctxhttp.ErrorResponse {
+	t.Helper()
+	var response ctxhttp.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+	}
+	return response
+}
+
+func TestHandler(t *testing.T) {
+	conf := ctxchi.Config{ShowMessage: true, ShowFields: true}
+
+	var handled error
+	ctxerr.AddHandleHook(func(err error) { handled = err })
+
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/users/{id}", conf.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return ctxerr.NewHTTP(r.Context(), "code", "", http.StatusBadRequest, "message")
+	}))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Error("status code did not match", rec.Code)
+	}
+	response := decode(t, rec)
+	if v := response.Error.Code; v != "code" {
+		t.Error("code did not match", v)
+	}
+	if v := response.Error.Fields[ctxerrfields.FieldKeyHTTPRoute]; v != "/users/{id}" {
+		t.Error("route did not match", v)
+	}
+	if v := ctxerr.AllFields(handled)[ctxerrfields.FieldKeyHTTPRoute]; v != "/users/{id}" {
+		t.Error("handled route did not match", v)
+	}
+}
+
+func TestRouteFields(t *testing.T) {
+	var route interface{}
+	r := chi.NewRouter()
+	r.Route("/orgs/{org}", func(r chi.Router) {
+		r.With(ctxchi.RouteFields).Get("/repos/{repo}", func(w http.ResponseWriter, r *http.Request) {
+			route = ctxerr.AllFields(ctxerr.New(r.Context(), "code"))[ctxerrfields.FieldKeyHTTPRoute]
+		})
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orgs/a/repos/b", nil))
+
+	if route != "/orgs/{org}/repos/{repo}" {
+		t.Error("route did not match", route)
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	var fields map[string]interface{}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, ctxchi.RequestFields)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		fields = ctxerr.AllFields(ctxerr.New(r.Context(), "code"))
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if fields[ctxerrfields.FieldKeyHTTPMethod] != http.MethodGet {
+		t.Error("method did not match", fields)
+	}
+	if v, _ := fields[ctxerrfields.FieldKeyRequestID].(string); v == "" {
+		t.Error("request ID from the chi middleware should be a field", fields)
+	}
+}
+
+func TestNotFoundAndMethodNotAllowed(t *testing.T) {
+	conf := ctxchi.Config{}
+	r := chi.NewRouter()
+	r.NotFound(conf.NotFound)
+	r.MethodNotAllowed(conf.MethodNotAllowed)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	r.Put("/", func(w http.ResponseWriter, r *http.Request) {})
+	r.Route("/users", func(r chi.Router) {
+		r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		expectedCode       string
+		expectedStatusCode int
+		expectedAllow      []string
+	}{
+		{name: "not found", method: http.MethodGet, path: "/missing", expectedCode: ctxchi.CodeNotFound, expectedStatusCode: http.StatusNotFound},
+		{
+			name:               "method not allowed",
+			method:             http.MethodPost,
+			path:               "/",
+			expectedCode:       ctxchi.CodeMethodNotAllowed,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedAllow:      []string{http.MethodGet, http.MethodPut},
+		},
+		{
+			name:               "sub router method not allowed",
+			method:             http.MethodGet,
+			path:               "/users/1",
+			expectedCode:       ctxchi.CodeMethodNotAllowed,
+			expectedStatusCode: http.StatusMethodNotAllowed,
+			expectedAllow:      []string{http.MethodDelete},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", rec.Code, tt.expectedStatusCode)
+			}
+			if v := rec.Header().Values("Allow"); !reflect.DeepEqual(v, tt.expectedAllow) {
+				t.Error("allow header did not match", v, tt.expectedAllow)
+			}
+			if v := decode(t, rec).Error.Code; v != tt.expectedCode {
+				t.Error("code did not match", v, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestNotFoundHTML(t *testing.T) {
+	conf := ctxchi.Config{}
+	r := chi.NewRouter()
+	r.NotFound(conf.NotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if v := rec.Header().Get("Content-Type"); !strings.HasPrefix(v, "text/html") {
+		t.Error("content type did not match", v)
+	}
+	if !strings.Contains(rec.Body.String(), "<h1>404 Not Found</h1>") {
+		t.Error("body should be the html page", rec.Body.String())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	conf := ctxchi.Config{ShowMessage: true, ShowFields: true}
+	r := chi.NewRouter()
+	r.Use(conf.Recover)
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) { panic(errors.New("boom")) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Error("status code did not match", rec.Code)
+	}
+	response := decode(t, rec)
+	if v := response.Error.Code; v != ctxchi.CodePanic {
+		t.Error("code did not match", v)
+	}
+	if v := response.Error.Fields[ctxerrfields.FieldKeyHTTPRoute]; v != "/users/{id}" {
+		t.Error("route did not match", v)
+	}
+	if _, ok := response.Error.Fields[ctxerrfields.FieldKeyPanicStack]; !ok {
+		t.Error("stack should be a field", response.Error.Fields)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/mvndaai/ctxerrhelper/chi
+
+go 1.22
+
+require (
+	github.com/go-chi/chi/v5 v5.2.5
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
+	github.com/mvndaai/ctxerrhelper/nethttp v0.1.0
+)
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+	github.com/mvndaai/ctxerrhelper/nethttp => ../nethttp
+)
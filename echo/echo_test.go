@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo"
 	"github.com/mvndaai/ctxerr"
 	ctxhttp "github.com/mvndaai/ctxerr/http"
-	ctxecho "github.com/mvndaai/ctxerr/http/framework/echo"
+	ctxecho "github.com/mvndaai/ctxerrhelper/echo"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"github.com/mvndaai/ctxerrhelper/traceid"
 )
 
 func TestErrorHandler(t *testing.T) {
@@ -51,9 +55,6 @@ func TestErrorHandler(t *testing.T) {
 	}
 
 	e := echo.New()
-	req := httptest.NewRequest("GET", "/", nil)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
 
 	handled := false
 	ctxerr.AddHandleHook(func(_ error) { handled = true })
@@ -61,6 +62,9 @@ func TestErrorHandler(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			handled = false
+			req := httptest.NewRequest("GET", "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
 
 			eh := ctxecho.ErrorHandler(true, false)
 			handler := func(c echo.Context) error {
@@ -88,3 +92,206 @@ func TestErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestFields(t *testing.T) {
+	e := echo.New()
+	e.Use(ctxecho.RequestFields())
+
+	var fields map[string]interface{}
+	e.GET("/users/:id", func(c echo.Context) error {
+		fields = ctxerr.AllFields(ctxerr.New(c.Request().Context(), "code"))
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "request-id")
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	req.Header.Set("User-Agent", "agent")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: "GET",
+		ctxerrfields.FieldKeyHTTPRoute:  "/users/:id",
+		ctxerrfields.FieldKeyRequestID:  "request-id",
+		ctxerrfields.FieldKeyClientIP:   "10.0.0.1",
+		ctxerrfields.FieldKeyUserAgent:  "agent",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestTraceHeaders(t *testing.T) {
+	restore := traceid.Install(traceid.FromContext)
+	defer restore()
+
+	e := echo.New()
+	e.HTTPErrorHandler = ctxecho.ErrorHandler(false, false)
+	e.Use(ctxecho.TraceHeaders())
+	e.GET("/", func(c echo.Context) error {
+		return ctxerr.NewHTTP(c.Request().Context(), "code", "", http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(traceid.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var response ctxhttp.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+	}
+	if v := response.Error.TraceID; v != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Error("trace ID did not match", v)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name            string
+		panicValue      interface{}
+		expectedMessage string
+	}{
+		{name: "value", panicValue: "boom", expectedMessage: "panic: boom"},
+		{name: "error", panicValue: errors.New("boom"), expectedMessage: "panic: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = ctxecho.ErrorHandler(true, true)
+			e.Use(ctxecho.Recover())
+			e.GET("/", func(c echo.Context) error { panic(tt.panicValue) })
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Error("status code did not match", rec.Code)
+			}
+
+			var response ctxhttp.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+			}
+			if v := response.Error.Code; v != ctxecho.CodePanic {
+				t.Error("code did not match", v)
+			}
+			if v := response.Error.Message; v != tt.expectedMessage {
+				t.Error("message did not match", v)
+			}
+			if v, _ := response.Error.Fields[ctxerrfields.FieldKeyPanicStack].(string); !strings.Contains(v, "goroutine") {
+				t.Error("stack should be a field", v)
+			}
+		})
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	e := echo.New()
+	e.Use(ctxecho.Recover())
+	e.GET("/", func(c echo.Context) error { panic(http.ErrAbortHandler) })
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Error("http.ErrAbortHandler should be re-panicked", r)
+		}
+	}()
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestErrorHandlerWrappedHTTPError(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedInternal   interface{}
+	}{
+		{
+			name:               "echo error",
+			err:                echo.NewHTTPError(http.StatusNotFound),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "wrapped echo error",
+			err:                ctxerr.Wrap(context.Background(), echo.NewHTTPError(http.StatusNotFound), "code"),
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "wrapped echo error with ctxerr status",
+			err:                ctxerr.WrapHTTP(context.Background(), echo.NewHTTPError(http.StatusNotFound), "code", "", http.StatusConflict),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "internal",
+			err:                ctxerr.Wrap(context.Background(), echo.NewHTTPError(http.StatusBadGateway).SetInternal(errors.New("internal")), "code"),
+			expectedStatusCode: http.StatusBadGateway,
+			expectedInternal:   "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+
+			ctxecho.ErrorHandler(true, true)(tt.err, c)
+
+			if rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", rec.Code, tt.expectedStatusCode)
+			}
+			var response ctxhttp.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+			}
+			if v := response.Error.Fields[ctxecho.FieldKeyInternal]; v != tt.expectedInternal {
+				t.Error("internal did not match", v, tt.expectedInternal)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerCommitted(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+
+	var handled bool
+	ctxerr.AddHandleHook(func(err error) {
+		if err.Error() == "committed" {
+			handled = true
+		}
+	})
+
+	if err := c.String(http.StatusOK, "ok"); err != nil {
+		t.Fatal(err)
+	}
+	ctxecho.ErrorHandlerWithOptions(ctxecho.Options{
+		LogError: func(err error) { t.Error("nothing should be written", err) },
+	})(ctxerr.New(c.Request().Context(), "code", "committed"), c)
+
+	if !handled {
+		t.Error("error should be handled even when committed")
+	}
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Error("committed response should not change", rec.Code, rec.Body.String())
+	}
+}
+
+func TestErrorHandlerHead(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodHead, "/", nil), rec)
+
+	ctxecho.ErrorHandler(true, true)(ctxerr.NewHTTP(c.Request().Context(), "code", "", http.StatusNotFound), c)
+
+	if rec.Code != http.StatusNotFound {
+		t.Error("status code did not match", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Error("HEAD should not have a body", rec.Body.String())
+	}
+}
@@ -4,8 +4,10 @@ go 1.20
 
 require (
 	github.com/labstack/echo v3.3.10+incompatible
-	github.com/mvndaai/ctxerr v0.12.0
-	github.com/mvndaai/ctxerr/http/framework/echo v0.0.0-20230228193256-2817a2fe0b2a
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
+	github.com/mvndaai/ctxerrhelper/nethttp v0.1.0
+	github.com/mvndaai/ctxerrhelper/traceid v0.1.0
 )
 
 require (
@@ -19,3 +21,10 @@ require (
 	golang.org/x/sys v0.10.0 // indirect
 	golang.org/x/text v0.11.0 // indirect
 )
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+	github.com/mvndaai/ctxerrhelper/nethttp => ../nethttp
+	github.com/mvndaai/ctxerrhelper/traceid => ../traceid
+)
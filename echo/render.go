@@ -0,0 +1,63 @@
+package echo
+
+import (
+	"encoding/json"
+
+	"github.com/labstack/echo"
+	"github.com/mvndaai/ctxerr/http"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+// Renderer writes the error response for a media type
+type Renderer = ctxnethttp.Renderer[echo.Context]
+
+// MIMEApplicationProblemJSON is the RFC 7807 media type for problem details
+const MIMEApplicationProblemJSON = ctxnethttp.MIMEApplicationProblemJSON
+
+// DefaultRenderers returns the built in renderers by media type
+func DefaultRenderers() map[string]Renderer {
+	return map[string]Renderer{
+		echo.MIMEApplicationJSON:   RenderJSON,
+		MIMEApplicationProblemJSON: RenderProblemJSON,
+		echo.MIMETextHTML:          RenderHTML,
+		echo.MIMETextPlain:         RenderText,
+	}
+}
+
+// Negotiate returns the renderer for the media range in the Accept header with the highest quality using ctxnethttp.Negotiate.
+// Nil is returned when nothing matches or the best match is */* so the caller can use its default.
+func Negotiate(accept string, renderers map[string]Renderer) Renderer {
+	r, _ := ctxnethttp.Negotiate(accept, renderers)
+	return r
+}
+
+// RenderJSON writes the ctxerr/http ErrorResponse as JSON
+func RenderJSON(c echo.Context, statusCode int, response http.ErrorResponse) error {
+	return c.JSON(statusCode, response)
+}
+
+// Problem is an RFC 7807 problem details object with the ctxerr response as extension members
+type Problem = ctxnethttp.Problem
+
+// RenderProblemJSON writes the response as RFC 7807 application/problem+json
+func RenderProblemJSON(c echo.Context, statusCode int, response http.ErrorResponse) error {
+	b, err := json.Marshal(ctxnethttp.NewProblem(statusCode, response, c.Request().URL.Path))
+	if err != nil {
+		return err
+	}
+	return c.Blob(statusCode, MIMEApplicationProblemJSON, b)
+}
+
+// RenderHTML writes the response as the simple HTML error page from ctxnethttp.HTML
+func RenderHTML(c echo.Context, statusCode int, response http.ErrorResponse) error {
+	html, err := ctxnethttp.HTML(statusCode, response)
+	if err != nil {
+		return err
+	}
+	return c.HTML(statusCode, html)
+}
+
+// RenderText writes the response as plain text
+func RenderText(c echo.Context, statusCode int, response http.ErrorResponse) error {
+	return c.String(statusCode, ctxnethttp.Text(statusCode, response))
+}
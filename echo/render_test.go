@@ -0,0 +1,59 @@
+package echo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/mvndaai/ctxerr"
+	ctxecho "github.com/mvndaai/ctxerrhelper/echo"
+)
+
+func TestErrorHandlerNegotiation(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedBody        []string
+	}{
+		{name: "default", accept: "", expectedContentType: echo.MIMEApplicationJSON, expectedBody: []string{`"code":"code"`}},
+		{name: "json", accept: "application/json", expectedContentType: echo.MIMEApplicationJSON, expectedBody: []string{`"code":"code"`}},
+		{
+			name:                "problem",
+			accept:              "application/problem+json",
+			expectedContentType: ctxecho.MIMEApplicationProblemJSON,
+			expectedBody:        []string{`"type":"about:blank"`, `"title":"Bad Request"`, `"status":400`, `"detail":"message"`, `"instance":"/path"`, `"code":"code"`},
+		},
+		{name: "html", accept: "text/html", expectedContentType: echo.MIMETextHTML, expectedBody: []string{"<h1>400 Bad Request</h1>", "<p>message</p>", "<code>code</code>"}},
+		{name: "text", accept: "text/plain", expectedContentType: echo.MIMETextPlain, expectedBody: []string{"400 Bad Request\n", "message: message\n", "code: code\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest("GET", "/path", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			ctxecho.ErrorHandler(true, false)(ctxerr.NewHTTP(req.Context(), "code", "", http.StatusBadRequest, "message"), c)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Error("status code did not match", rec.Code)
+			}
+			if v := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(v, tt.expectedContentType) {
+				t.Error("content type did not match", v, tt.expectedContentType)
+			}
+			if v := rec.Header().Get(echo.HeaderVary); v != echo.HeaderAccept {
+				t.Error("vary header did not match", v)
+			}
+			for _, s := range tt.expectedBody {
+				if !strings.Contains(rec.Body.String(), s) {
+					t.Errorf("body did not contain %s\n%s", s, rec.Body.String())
+				}
+			}
+		})
+	}
+}
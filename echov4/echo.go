@@ -0,0 +1,132 @@
+/*
+Package echov4 has functions to use with echo v4 (https://echo.labstack.com).
+
+	import ctxechov4 "github.com/mvndaai/ctxerrhelper/echov4"
+
+	func main() {
+		...
+		e.HTTPErrorHandler = ctxechov4.ErrorHandler(config.ShowMessage, config.ShowFields)
+		...
+	}
+*/
+package echov4
+
+import (
+	"errors"
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mvndaai/ctxerr"
+	"github.com/mvndaai/ctxerr/http"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+	"github.com/mvndaai/ctxerrhelper/traceid"
+)
+
+// FieldKeyInternal is the response field key for the Internal error of an echo.HTTPError
+const FieldKeyInternal = "echo_internal"
+
+// ErrorHandler implements an echo Custom  HTTP Error Handler.
+// This uses the ctxerr/http package to return a standardized response.
+// See https://echo.labstack.com/guide/error-handling for more information on error handlers.
+func ErrorHandler(showMessage, showFields bool) func(err error, c echo.Context) {
+	return ErrorHandlerWithOptions(Options{ShowMessage: showMessage, ShowFields: showFields})
+}
+
+// Options configure ErrorHandlerWithOptions
+type Options = ctxnethttp.Options[echo.Context]
+
+// ErrorHandlerWithOptions implements an echo Custom HTTP Error Handler like ErrorHandler using ctxnethttp.ErrorHandler.
+// The response is rendered by negotiating the request Accept header against the options renderers.
+// The error is always handled but nothing is written if the response was already committed and HEAD requests get no body.
+func ErrorHandlerWithOptions(o Options) func(err error, c echo.Context) {
+	return ctxnethttp.ErrorHandler(o, ctxnethttp.Framework[echo.Context]{
+		Request:               func(c echo.Context) *nethttp.Request { return c.Request() },
+		Header:                func(c echo.Context) nethttp.Header { return c.Response().Header() },
+		Committed:             func(c echo.Context) bool { return c.Response().Committed },
+		NoContent:             echo.Context.NoContent,
+		StatusCodeAndResponse: statusCodeAndResponse,
+		LogError:              func(c echo.Context, err error) { c.Logger().Error(err) },
+		Renderers:             DefaultRenderers(),
+		DefaultRenderer:       RenderJSON,
+	})
+}
+
+// statusCodeAndResponse adds the status code, message and Internal error of an echo.HTTPError, even when it is wrapped,
+// to ctxnethttp.StatusCodeAndResponse
+func statusCodeAndResponse(err error, c echo.Context, showMessage, showFields bool) (int, http.ErrorResponse) {
+	statusCode, response := ctxnethttp.StatusCodeAndResponse(err, c.Request(), showMessage, showFields)
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		statusCode = ctxnethttp.FrameworkStatusCode(err, statusCode, he.Code)
+		if showMessage && err == error(he) {
+			response.Error.Message = fmt.Sprintf("%s", he.Message)
+		}
+		if showFields && he.Internal != nil {
+			if response.Error.Fields == nil {
+				response.Error.Fields = map[string]interface{}{}
+			}
+			response.Error.Fields[FieldKeyInternal] = he.Internal.Error()
+		}
+	}
+	return statusCode, response
+}
+
+// RequestFields is a middleware that sets the request method, route path, request ID, client IP and user agent
+// as ctxerr fields on the request context so every error created in a handler carries them.
+// The request ID is read from the X-Request-ID request header or the response header set by echo's RequestID middleware.
+func RequestFields() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+			fields := ctxnethttp.Fields(req, c.Path(), c.RealIP(), requestID)
+			c.SetRequest(req.WithContext(ctxerr.SetFields(req.Context(), fields)))
+			return next(c)
+		}
+	}
+}
+
+// TraceHeaders is a middleware that stores the trace ID from the traceparent, B3 or X-Request-Id request headers
+// on the request context with the traceid package. Install traceid.FromContext, after any tracing providers,
+// so the ErrorHandler finds it with http.TraceID.
+//
+//	traceid.Install(ctxotel.TraceID, traceid.FromContext)
+func TraceHeaders() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if id := traceid.FromHeader(c.Request().Header); id != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(traceid.NewContext(req.Context(), id)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// CodePanic is the ctxerr code of errors created by Recover
+const CodePanic = ctxnethttp.CodePanic
+
+// Recover is a middleware that converts a panic with ctxnethttp.PanicError.
+// The error is returned so echo routes it through the HTTPErrorHandler like any other error.
+func Recover() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == nethttp.ErrAbortHandler {
+					panic(r)
+				}
+				err = ctxnethttp.PanicError(c.Request().Context(), r)
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package echov4_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxechov4 "github.com/mvndaai/ctxerrhelper/echov4"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+// The shared error handler flow is tested in the nethttp package. These tests cover the echo v4 adapters.
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name                string
+		method              string
+		path                string
+		accept              string
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        []string
+	}{
+		{
+			name:                "ctxerr",
+			path:                "/ctxerr",
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedBody:        []string{`"code":"code"`, `"message":"message"`, `"` + ctxerrfields.FieldKeyHTTPRoute + `":"/ctxerr"`},
+		},
+		{
+			name:                "routing error",
+			path:                "/missing",
+			expectedStatusCode:  http.StatusNotFound,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedBody:        []string{`"message":"Not Found"`},
+		},
+		{
+			name:                "wrapped internal",
+			path:                "/internal",
+			expectedStatusCode:  http.StatusBadGateway,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedBody:        []string{`"` + ctxechov4.FieldKeyInternal + `":"internal"`},
+		},
+		{
+			name:                "panic",
+			path:                "/panic",
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: echo.MIMEApplicationJSON,
+			expectedBody:        []string{`"code":"` + ctxechov4.CodePanic + `"`, `"message":"panic: boom"`},
+		},
+		{
+			name:                "problem",
+			path:                "/ctxerr",
+			accept:              ctxechov4.MIMEApplicationProblemJSON,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: ctxechov4.MIMEApplicationProblemJSON,
+			expectedBody:        []string{`"title":"Bad Request"`, `"instance":"/ctxerr"`},
+		},
+		{
+			name:                "html",
+			path:                "/ctxerr",
+			accept:              echo.MIMETextHTML,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: echo.MIMETextHTML,
+			expectedBody:        []string{"<h1>400 Bad Request</h1>"},
+		},
+		{
+			name:                "text",
+			path:                "/ctxerr",
+			accept:              echo.MIMETextPlain,
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: echo.MIMETextPlain,
+			expectedBody:        []string{"code: code\n"},
+		},
+		{
+			name:               "head",
+			method:             http.MethodHead,
+			path:               "/missing",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "committed",
+			path:               "/committed",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       []string{"ok"},
+		},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = ctxechov4.ErrorHandler(true, true)
+	e.Use(ctxechov4.Recover(), ctxechov4.RequestFields())
+	e.GET("/ctxerr", func(c echo.Context) error {
+		return ctxerr.NewHTTP(c.Request().Context(), "code", "", http.StatusBadRequest, "message")
+	})
+	e.GET("/internal", func(c echo.Context) error {
+		return ctxerr.Wrap(c.Request().Context(), echo.NewHTTPError(http.StatusBadGateway).SetInternal(errors.New("internal")), "code")
+	})
+	e.GET("/panic", func(c echo.Context) error { panic("boom") })
+	e.GET("/committed", func(c echo.Context) error {
+		_ = c.String(http.StatusOK, "ok")
+		return errors.New("committed")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", rec.Code, tt.expectedStatusCode)
+			}
+			if v := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(v, tt.expectedContentType) {
+				t.Error("content type did not match", v, tt.expectedContentType)
+			}
+			if len(tt.expectedBody) == 0 && rec.Body.Len() != 0 {
+				t.Error("body should be empty", rec.Body.String())
+			}
+			for _, s := range tt.expectedBody {
+				if !strings.Contains(rec.Body.String(), s) {
+					t.Errorf("body did not contain %s\n%s", s, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	e := echo.New()
+	e.Use(ctxechov4.RequestFields())
+
+	var fields map[string]interface{}
+	e.GET("/users/:id", func(c echo.Context) error {
+		fields = ctxerr.AllFields(ctxerr.New(c.Request().Context(), "code"))
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "request-id")
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPRoute: "/users/:id",
+		ctxerrfields.FieldKeyRequestID: "request-id",
+		ctxerrfields.FieldKeyClientIP:  "10.0.0.1",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestErrorHandlerLogger(t *testing.T) {
+	e := echo.New()
+	var logged strings.Builder
+	e.Logger.SetOutput(&logged)
+	c := e.NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+
+	ctxechov4.ErrorHandlerWithOptions(ctxechov4.Options{
+		DefaultRenderer: func(echo.Context, int, ctxhttp.ErrorResponse) error { return errors.New("write") },
+	})(ctxerr.New(context.Background(), "code"), c)
+
+	if !strings.Contains(logged.String(), "write") {
+		t.Error("write error should be logged with the echo logger", logged.String())
+	}
+}
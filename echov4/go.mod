@@ -0,0 +1,30 @@
+module github.com/mvndaai/ctxerrhelper/echov4
+
+go 1.20
+
+require (
+	github.com/labstack/echo/v4 v4.12.0
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
+	github.com/mvndaai/ctxerrhelper/nethttp v0.1.0
+	github.com/mvndaai/ctxerrhelper/traceid v0.1.0
+)
+
+require (
+	github.com/labstack/gommon v0.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.22.0 // indirect
+	golang.org/x/net v0.24.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+	github.com/mvndaai/ctxerrhelper/nethttp => ../nethttp
+	github.com/mvndaai/ctxerrhelper/traceid => ../traceid
+)
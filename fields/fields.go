@@ -0,0 +1,130 @@
+/*
+Package ctxerrfields has helpers for rendering ctxerr fields that are shared by the other helper packages.
+
+	import ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+
+	func main() {
+		...
+		b, err := json.Marshal(ctxerrfields.Sanitize(ctxerr.AllFields(err)))
+		...
+	}
+*/
+package ctxerrfields
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Sanitize returns a copy of the fields with every value passed through Value so the map can always be marshaled to JSON
+func Sanitize(fields map[string]any) map[string]any {
+	if fields == nil {
+		return nil
+	}
+	m := make(map[string]any, len(fields))
+	for k, v := range fields {
+		m[k] = Value(v)
+	}
+	return m
+}
+
+// Value returns a version of the value that can be marshaled to JSON.
+//
+// Values implementing json.Marshaler are kept, errors use Error(), encoding.TextMarshaler uses MarshalText() and
+// fmt.Stringer uses String(). Funcs and channels become their type and any other value that cannot be marshaled
+// is formatted with fmt.
+func Value(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Sprintf("%T", v)
+	}
+
+	switch t := v.(type) {
+	case json.Marshaler:
+		if _, err := json.Marshal(t); err == nil {
+			return v
+		}
+	case error:
+		return t.Error()
+	case encoding.TextMarshaler:
+		if b, err := t.MarshalText(); err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return t.String()
+	}
+
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return v
+}
+
+// String renders a value passed through Value as a string for places that only take strings, like span attributes
+// or metadata. Strings are kept and anything else is marshaled to JSON.
+func String(v any) string {
+	v = Value(v)
+	if s, ok := v.(string); ok {
+		return s
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// Scalar converts a value to a string, bool, int64 or float64 for attributes that only have those types, like span
+// attributes. Integers that fit in an int64 and floats are converted and anything else is rendered with String.
+func Scalar(v any) any {
+	switch v := v.(type) {
+	case string, bool, int64, float64:
+		return v
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint:
+		if uint64(v) <= math.MaxInt64 {
+			return int64(v)
+		}
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+	case float32:
+		return float64(v)
+	}
+	return String(v)
+}
+
+// SortedKeys returns the keys of the fields sorted so attributes are added in a stable order
+func SortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+package ctxerrfields_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+type cyclic struct {
+	Name string
+	Next *cyclic
+}
+
+func TestValue(t *testing.T) {
+	now := time.Now()
+	c := &cyclic{Name: "a"}
+	c.Next = c
+
+	tests := []struct {
+		name     string
+		in       any
+		expected any
+	}{
+		{name: "nil", in: nil, expected: nil},
+		{name: "string", in: "a", expected: "a"},
+		{name: "int", in: 1, expected: 1},
+		{name: "map", in: map[string]int{"a": 1}, expected: map[string]int{"a": 1}},
+		{name: "nil pointer", in: (*stringer)(nil), expected: (*stringer)(nil)},
+		{name: "json marshaler", in: now, expected: now},
+		{name: "error", in: errors.New("err"), expected: "err"},
+		{name: "text marshaler", in: net.ParseIP("127.0.0.1"), expected: "127.0.0.1"},
+		{name: "stringer", in: stringer{}, expected: "stringer"},
+		{name: "func", in: func() {}, expected: "func()"},
+		{name: "chan", in: make(chan int), expected: "chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := ctxerrfields.Value(tt.in)
+			if !reflect.DeepEqual(out, tt.expected) {
+				t.Errorf("value did not match\n%#v\n%#v", out, tt.expected)
+			}
+		})
+	}
+
+	t.Run("cyclic", func(t *testing.T) {
+		out, ok := ctxerrfields.Value(c).(string)
+		if !ok || !strings.Contains(out, "Name:a") {
+			t.Errorf("cyclic struct should be formatted %#v", out)
+		}
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	if out := ctxerrfields.Sanitize(nil); out != nil {
+		t.Error("nil should stay nil", out)
+	}
+
+	in := map[string]any{"a": "b", "func": func() {}, "err": errors.New("err")}
+	b, err := json.Marshal(ctxerrfields.Sanitize(in))
+	if err != nil {
+		t.Fatal("sanitized fields should marshal", err)
+	}
+	if expected := `{"a":"b","err":"err","func":"func()"}`; string(b) != expected {
+		t.Errorf("json did not match\n%s\n%s", b, expected)
+	}
+	if _, ok := in["func"].(func()); !ok {
+		t.Error("input should not be modified", in)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       any
+		expected string
+	}{
+		{name: "nil", in: nil, expected: "null"},
+		{name: "string", in: "a", expected: "a"},
+		{name: "int", in: 1, expected: "1"},
+		{name: "map", in: map[string]int{"a": 1}, expected: `{"a":1}`},
+		{name: "error", in: errors.New("err"), expected: "err"},
+		{name: "func", in: func() {}, expected: "func()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := ctxerrfields.String(tt.in); v != tt.expected {
+				t.Errorf("string did not match [%s] [%s]", v, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       any
+		expected any
+	}{
+		{name: "string", in: "a", expected: "a"},
+		{name: "bool", in: true, expected: true},
+		{name: "int", in: 1, expected: int64(1)},
+		{name: "int8", in: int8(-1), expected: int64(-1)},
+		{name: "uint", in: uint(1), expected: int64(1)},
+		{name: "uint64", in: uint64(1), expected: int64(1)},
+		{name: "large uint64", in: uint64(math.MaxUint64), expected: "18446744073709551615"},
+		{name: "float32", in: float32(1.5), expected: 1.5},
+		{name: "slice", in: []int{1}, expected: "[1]"},
+		{name: "nil", in: nil, expected: "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := ctxerrfields.Scalar(tt.in); v != tt.expected {
+				t.Errorf("scalar did not match %#v %#v", v, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	keys := ctxerrfields.SortedKeys(map[string]any{"b": 1, "c": 2, "a": 3})
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Error("keys did not match", keys)
+	}
+}
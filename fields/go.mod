@@ -0,0 +1,5 @@
+module github.com/mvndaai/ctxerrhelper/fields
+
+go 1.20
+
+require github.com/mvndaai/ctxerr v0.13.0
@@ -0,0 +1,29 @@
+package ctxerrfields
+
+// Field keys the framework helpers use for request metadata
+const (
+	FieldKeyHTTPMethod = "http_method"
+	FieldKeyHTTPRoute  = "http_route"
+	FieldKeyRequestID  = "request_id"
+	FieldKeyClientIP   = "client_ip"
+	FieldKeyUserAgent  = "user_agent"
+)
+
+// Field keys the grpc helpers use for request metadata
+const (
+	FieldKeyGRPCMethod       = "grpc_method"
+	FieldKeyGRPCPeer         = "grpc_peer"
+	FieldKeyGRPCRemoteMethod = "grpc_remote_method"
+	FieldKeyGRPCCode         = "grpc_code"
+)
+
+// FieldKeyPanicStack is the field key the recover middlewares use for the stack of a panic
+const FieldKeyPanicStack = "panic_stack"
+
+// Field keys the tracing helpers use for the span an error was created in
+const (
+	FieldKeyTraceID      = "trace_id"
+	FieldKeySpanID       = "span_id"
+	FieldKeyTraceSampled = "trace_sampled"
+	FieldKeyTraceURL     = "trace_url"
+)
@@ -0,0 +1,39 @@
+package ctxerrfields
+
+// Selection chooses which ctxerr fields a helper uses, like the fields of a log entry or Slack message or the
+// attributes of a metric, so the same configuration can be shared between them
+type Selection struct {
+	// Include limits the fields to these keys. All fields are included when empty
+	Include []string
+	// Exclude removes these keys from the included fields
+	Exclude []string
+}
+
+// IsZero tells if the selection keeps every field
+func (s Selection) IsZero() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0
+}
+
+// Select returns the selected fields. The map is returned as is when the selection keeps every field
+func (s Selection) Select(fields map[string]any) map[string]any {
+	if fields == nil || s.IsZero() {
+		return fields
+	}
+
+	m := make(map[string]any, len(fields))
+	if len(s.Include) == 0 {
+		for k, v := range fields {
+			m[k] = v
+		}
+	} else {
+		for _, k := range s.Include {
+			if v, ok := fields[k]; ok {
+				m[k] = v
+			}
+		}
+	}
+	for _, k := range s.Exclude {
+		delete(m, k)
+	}
+	return m
+}
@@ -0,0 +1,39 @@
+package ctxerrfields_test
+
+import (
+	"reflect"
+	"testing"
+
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+func TestSelection(t *testing.T) {
+	fields := map[string]any{"a": 1, "b": 2, "c": 3}
+
+	tests := []struct {
+		name      string
+		selection ctxerrfields.Selection
+		expected  map[string]any
+	}{
+		{name: "zero", expected: map[string]any{"a": 1, "b": 2, "c": 3}},
+		{name: "include", selection: ctxerrfields.Selection{Include: []string{"a", "missing"}}, expected: map[string]any{"a": 1}},
+		{name: "exclude", selection: ctxerrfields.Selection{Exclude: []string{"a"}}, expected: map[string]any{"b": 2, "c": 3}},
+		{name: "both", selection: ctxerrfields.Selection{Include: []string{"a", "b"}, Exclude: []string{"b"}}, expected: map[string]any{"a": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := tt.selection.Select(fields)
+			if !reflect.DeepEqual(out, tt.expected) {
+				t.Errorf("fields did not match\n%v\n%v", out, tt.expected)
+			}
+		})
+	}
+
+	if len(fields) != 3 {
+		t.Error("fields should not be modified", fields)
+	}
+	if out := (ctxerrfields.Selection{Include: []string{"a"}}).Select(nil); out != nil {
+		t.Error("nil should stay nil", out)
+	}
+}
@@ -0,0 +1,73 @@
+package ctxerrfields
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mvndaai/ctxerr"
+)
+
+// StatusCode converts a ctxerr status code field value to an int.
+// Any integer type, whole floats, numeric strings and json.Number are accepted. False is returned for anything else.
+//
+// Use ErrorStatusCode to read it from an error.
+func StatusCode(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float32:
+		return wholeFloat(float64(v))
+	case float64:
+		return wholeFloat(v)
+	case json.Number:
+		return atoi(string(v))
+	case string:
+		return atoi(v)
+	}
+	return 0, false
+}
+
+func wholeFloat(f float64) (int, bool) {
+	if f != math.Trunc(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func atoi(s string) (int, bool) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	return i, err == nil
+}
+
+// ErrorStatusCode returns the ctxerr status code field of an error converted with StatusCode
+func ErrorStatusCode(err error) (int, bool) {
+	return StatusCode(ctxerr.AllFields(err)[ctxerr.FieldKeyStatusCode])
+}
+
+// IsWarning tells if an error has an http status code below 500, like the 4xx errors of bad requests.
+// Errors without a status code are not warnings.
+func IsWarning(err error) bool {
+	statusCode, ok := ErrorStatusCode(err)
+	return ok && statusCode > 0 && statusCode < http.StatusInternalServerError
+}
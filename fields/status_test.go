@@ -0,0 +1,65 @@
+package ctxerrfields_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         any
+		expected   int
+		expectedOK bool
+	}{
+		{name: "nil", in: nil},
+		{name: "int", in: 404, expected: 404, expectedOK: true},
+		{name: "int64", in: int64(404), expected: 404, expectedOK: true},
+		{name: "uint16", in: uint16(404), expected: 404, expectedOK: true},
+		{name: "float64", in: float64(404), expected: 404, expectedOK: true},
+		{name: "fractional float", in: 404.5},
+		{name: "string", in: "404", expected: 404, expectedOK: true},
+		{name: "padded string", in: " 404 ", expected: 404, expectedOK: true},
+		{name: "invalid string", in: "not found"},
+		{name: "json number", in: json.Number("404"), expected: 404, expectedOK: true},
+		{name: "bool", in: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := ctxerrfields.StatusCode(tt.in)
+			if v != tt.expected || ok != tt.expectedOK {
+				t.Errorf("status code did not match %d %v, %d %v", v, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestIsWarning(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "go error", err: errors.New("err"), expected: false},
+		{name: "no status", err: ctxerr.New(ctx, "code"), expected: false},
+		{name: "4xx", err: ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound), expected: true},
+		{name: "5xx", err: ctxerr.NewHTTP(ctx, "code", "", http.StatusBadGateway), expected: false},
+		{name: "int64", err: ctxerr.New(ctxerr.SetField(ctx, ctxerr.FieldKeyStatusCode, int64(http.StatusNotFound)), "code"), expected: true},
+		{name: "string", err: ctxerr.New(ctxerr.SetField(ctx, ctxerr.FieldKeyStatusCode, "404"), "code"), expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := ctxerrfields.IsWarning(tt.err); v != tt.expected {
+				t.Error("warning did not match", v, tt.expected)
+			}
+		})
+	}
+}
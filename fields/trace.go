@@ -0,0 +1,18 @@
+package ctxerrfields
+
+import "strings"
+
+// TraceFields returns the fields the tracing helpers add for a span.
+// When urlTemplate is set a link to a trace viewer is added with the placeholders {trace_id} and {span_id} replaced,
+// like "https://tracing.example.com/trace/{trace_id}".
+func TraceFields(traceID, spanID string, sampled bool, urlTemplate string) map[string]any {
+	fields := map[string]any{
+		FieldKeyTraceID:      traceID,
+		FieldKeySpanID:       spanID,
+		FieldKeyTraceSampled: sampled,
+	}
+	if urlTemplate != "" {
+		fields[FieldKeyTraceURL] = strings.NewReplacer("{trace_id}", traceID, "{span_id}", spanID).Replace(urlTemplate)
+	}
+	return fields
+}
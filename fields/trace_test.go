@@ -0,0 +1,25 @@
+package ctxerrfields_test
+
+import (
+	"reflect"
+	"testing"
+
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+func TestTraceFields(t *testing.T) {
+	fields := ctxerrfields.TraceFields("trace", "span", true, "")
+	expected := map[string]any{
+		ctxerrfields.FieldKeyTraceID:      "trace",
+		ctxerrfields.FieldKeySpanID:       "span",
+		ctxerrfields.FieldKeyTraceSampled: true,
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("fields did not match\n%v\n%v", fields, expected)
+	}
+
+	fields = ctxerrfields.TraceFields("trace", "span", false, "https://tracing.example.com/trace/{trace_id}?span={span_id}")
+	if v := fields[ctxerrfields.FieldKeyTraceURL]; v != "https://tracing.example.com/trace/trace?span=span" {
+		t.Error("url did not match", v)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Package gin has functions to use with gin (https://gin-gonic.com).
+
+	import ctxgin "github.com/mvndaai/ctxerrhelper/gin"
+
+	func main() {
+		...
+		r := gin.New()
+		r.Use(ctxgin.ErrorHandler(config.ShowMessage, config.ShowFields), ctxgin.Recover(), ctxgin.RequestFields())
+		...
+	}
+*/
+package gin
+
+import (
+	nethttp "net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mvndaai/ctxerr"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+// HeaderXRequestID is the header the request ID is read from
+const HeaderXRequestID = "X-Request-ID"
+
+// ErrorHandler is a middleware that calls ctxerr.Handle on every error added with c.Error.
+// The last error is written with ctxnethttp.WriteResponse, which negotiates JSON, problem details, HTML or text
+// from the Accept header, unless something was already written.
+func ErrorHandler(showMessage, showFields bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		for _, ge := range c.Errors {
+			ctxerr.Handle(ge.Err)
+		}
+		if c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		statusCode, response := ctxnethttp.StatusCodeAndResponse(err, c.Request, showMessage, showFields)
+		if err := ctxnethttp.WriteResponse(c.Writer, c.Request, statusCode, response); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// RequestFields is a middleware that sets the request method, route path, request ID, client IP and user agent
+// as ctxerr fields on the request context so every error created in a handler carries them.
+// The request ID is read from the X-Request-ID request header or the response header.
+func RequestFields() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderXRequestID)
+		if requestID == "" {
+			requestID = c.Writer.Header().Get(HeaderXRequestID)
+		}
+		fields := ctxnethttp.Fields(c.Request, c.FullPath(), c.ClientIP(), requestID)
+		c.Request = c.Request.WithContext(ctxerr.SetFields(c.Request.Context(), fields))
+		c.Next()
+	}
+}
+
+// CodePanic is the ctxerr code of errors created by Recover
+const CodePanic = ctxnethttp.CodePanic
+
+// Recover is a middleware that converts a panic with ctxnethttp.PanicError.
+// The error is added with c.Error so it must be used after ErrorHandler.
+func Recover() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == nethttp.ErrAbortHandler {
+				panic(r)
+			}
+			_ = c.Error(ctxnethttp.PanicError(c.Request.Context(), r))
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,196 @@
+package gin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxgin "github.com/mvndaai/ctxerrhelper/gin"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestErrorHandler(t *testing.T) {
+	code := "code"
+	message := "message"
+
+	tests := []struct {
+		name               string
+		toErr              func(context.Context) error
+		expectedCode       string
+		expectedStatusCode int
+	}{
+		{
+			name: "ctxerr",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, code, "", http.StatusBadRequest, message)
+			},
+			expectedCode:       code,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "go error",
+			toErr: func(ctx context.Context) error {
+				return errors.New(message)
+			},
+			expectedCode:       "",
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	var handled []error
+	ctxerr.AddHandleHook(func(err error) { handled = append(handled, err) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			r := gin.New()
+			r.Use(ctxgin.ErrorHandler(true, false))
+			r.GET("/", func(c *gin.Context) {
+				_ = c.Error(tt.toErr(c.Request.Context()))
+			})
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if len(handled) != 1 {
+				t.Error("error not handled", handled)
+			}
+			if rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", rec.Code, tt.expectedStatusCode)
+			}
+
+			var response ctxhttp.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+			}
+			if v := response.Error.Code; v != tt.expectedCode {
+				t.Error("code did not match", v, tt.expectedCode)
+			}
+			if v := response.Error.Message; v != message {
+				t.Error("message did not match", v, message)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerWritten(t *testing.T) {
+	r := gin.New()
+	r.Use(ctxgin.ErrorHandler(true, true))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+		_ = c.Error(errors.New("after write"))
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Error("written response should not change", rec.Code, rec.Body.String())
+	}
+}
+
+func TestErrorHandlerNegotiation(t *testing.T) {
+	tests := []struct {
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{accept: "", expectedContentType: "application/json", expectedBody: `"code":"code"`},
+		{accept: "application/problem+json", expectedContentType: ctxnethttp.MIMEApplicationProblemJSON, expectedBody: `"status":400`},
+		{accept: "text/html", expectedContentType: "text/html", expectedBody: "<h1>400 Bad Request</h1>"},
+		{accept: "text/plain", expectedContentType: "text/plain", expectedBody: "code: code\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ctxgin.ErrorHandler(false, false))
+			r.GET("/", func(c *gin.Context) {
+				_ = c.Error(ctxerr.NewHTTP(c.Request.Context(), "code", "", http.StatusBadRequest))
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Error("status code did not match", rec.Code)
+			}
+			if v := rec.Header().Get("Content-Type"); !strings.HasPrefix(v, tt.expectedContentType) {
+				t.Error("content type did not match", v, tt.expectedContentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.expectedBody) {
+				t.Errorf("body did not contain %s\n%s", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	r := gin.New()
+	r.Use(ctxgin.RequestFields())
+
+	var fields map[string]interface{}
+	r.GET("/users/:id", func(c *gin.Context) {
+		fields = ctxerr.AllFields(ctxerr.New(c.Request.Context(), "code"))
+	})
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(ctxgin.HeaderXRequestID, "request-id")
+	req.Header.Set("User-Agent", "agent")
+	req.RemoteAddr = "10.0.0.1:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: "GET",
+		ctxerrfields.FieldKeyHTTPRoute:  "/users/:id",
+		ctxerrfields.FieldKeyRequestID:  "request-id",
+		ctxerrfields.FieldKeyClientIP:   "10.0.0.1",
+		ctxerrfields.FieldKeyUserAgent:  "agent",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	r := gin.New()
+	r.Use(ctxgin.ErrorHandler(true, true), ctxgin.Recover())
+	r.GET("/", func(c *gin.Context) { panic("boom") })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Error("status code did not match", rec.Code)
+	}
+
+	var response ctxhttp.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+	}
+	if v := response.Error.Code; v != ctxgin.CodePanic {
+		t.Error("code did not match", v)
+	}
+	if v := response.Error.Message; v != "panic: boom" {
+		t.Error("message did not match", v)
+	}
+	if v, _ := response.Error.Fields[ctxerrfields.FieldKeyPanicStack].(string); !strings.Contains(v, "goroutine") {
+		t.Error("stack should be a field", v)
+	}
+}
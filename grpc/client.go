@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor converts status errors from calls into ctxerr errors with FromStatus
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromStatus(ctx, method, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts status errors from opening, sending on and receiving from streams
+// into ctxerr errors with FromStatus
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromStatus(ctx, method, err)
+		}
+		return &clientStream{ClientStream: cs, ctx: ctx, method: method}, nil
+	}
+}
+
+// clientStream converts the errors of a grpc.ClientStream
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return FromStatus(s.ctx, s.method, s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return FromStatus(s.ctx, s.method, s.ClientStream.RecvMsg(m))
+}
+
+// FromStatus wraps a gRPC status error into a ctxerr error. Other errors, including io.EOF, are returned as is.
+// The ctxerr code is the errdetails.ErrorInfo reason set by ServerConfig, or the gRPC code if there is none.
+// The status code is the one from the server fields or the http equivalent of the gRPC code.
+// Fields the server included in the errdetails.ErrorInfo metadata are added unless the context already has them.
+// Metadata values are strings, so fields that were not strings on the server, which ServerConfig encodes as JSON,
+// are added as their JSON text, like "42" or {"a":1}, instead of being decoded.
+func FromStatus(ctx context.Context, method string, err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	code := st.Code().String()
+	var remote map[string]string
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.Reason != "" {
+				code = info.Reason
+			}
+			remote = info.Metadata
+			break
+		}
+	}
+
+	statusCode := HTTPFromCode(st.Code())
+	if v, convErr := strconv.Atoi(remote[ctxerr.FieldKeyStatusCode]); convErr == nil {
+		statusCode = v
+	}
+	action := remote[ctxerr.FieldKeyAction]
+
+	local := ctxerr.Fields(ctx)
+	fields := map[string]interface{}{}
+	for k, v := range remote {
+		switch k {
+		case ctxerr.FieldKeyCode, ctxerr.FieldKeyStatusCode, ctxerr.FieldKeyAction:
+			continue
+		}
+		if _, ok := local[k]; !ok {
+			fields[k] = v
+		}
+	}
+	fields[ctxerrfields.FieldKeyGRPCRemoteMethod] = method
+	fields[ctxerrfields.FieldKeyGRPCCode] = st.Code().String()
+
+	return ctxerr.WrapHTTP(ctxerr.SetFields(ctx, fields), err, code, action, statusCode)
+}
+
+// HTTPFromCode converts a gRPC code into the http status code with the closest meaning
+func HTTPFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
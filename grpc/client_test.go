@@ -0,0 +1,123 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxgrpc "github.com/mvndaai/ctxerrhelper/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	srv := &healthServer{toErr: func(ctx context.Context) error {
+		ctx = ctxerr.SetFields(ctx, map[string]interface{}{"remote": "a", "shared": "remote"})
+		return ctxerr.NewHTTP(ctx, "remote_code", "retry", http.StatusConflict, "message")
+	}}
+	client := newClient(t, ctxgrpc.ServerConfig{ShowFields: true}, srv, grpc.WithUnaryInterceptor(ctxgrpc.UnaryClientInterceptor()))
+
+	ctx := ctxerr.SetField(context.Background(), "shared", "local")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+	fields := ctxerr.AllFields(err)
+	expected := map[string]interface{}{
+		ctxerr.FieldKeyCode:                   "remote_code",
+		ctxerr.FieldKeyStatusCode:             http.StatusConflict,
+		ctxerr.FieldKeyAction:                 "retry",
+		"remote":                              "a",
+		"shared":                              "local",
+		ctxerrfields.FieldKeyGRPCRemoteMethod: "/grpc.health.v1.Health/Check",
+		ctxerrfields.FieldKeyGRPCCode:         codes.AlreadyExists.String(),
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+	if status.Code(err) != codes.AlreadyExists {
+		t.Error("the status should still be found", status.Code(err))
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	srv := &healthServer{toErr: func(ctx context.Context) error {
+		return ctxerr.NewHTTP(ctx, "remote_code", "", http.StatusForbidden, "message")
+	}}
+	client := newClient(t, ctxgrpc.ServerConfig{}, srv, grpc.WithStreamInterceptor(ctxgrpc.StreamClientInterceptor()))
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal("could not watch", err)
+	}
+	_, err = stream.Recv()
+
+	fields := ctxerr.AllFields(err)
+	if fields[ctxerr.FieldKeyCode] != "remote_code" {
+		t.Error("code did not match", fields)
+	}
+	if fields[ctxerr.FieldKeyStatusCode] != http.StatusForbidden {
+		t.Error("status code did not match", fields)
+	}
+}
+
+func TestDownstreamCode(t *testing.T) {
+	for _, code := range []codes.Code{codes.Aborted, codes.FailedPrecondition, codes.OutOfRange, codes.DataLoss, codes.Unknown, codes.NotFound} {
+		t.Run(code.String(), func(t *testing.T) {
+			downstream := newClient(t, ctxgrpc.ServerConfig{}, &healthServer{toErr: func(ctx context.Context) error {
+				return status.Error(code, "downstream")
+			}}, grpc.WithUnaryInterceptor(ctxgrpc.UnaryClientInterceptor()))
+
+			upstream := newClient(t, ctxgrpc.ServerConfig{}, &healthServer{toErr: func(ctx context.Context) error {
+				_, err := downstream.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+				return err
+			}})
+
+			_, err := upstream.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			if status.Code(err) != code {
+				t.Error("the downstream code should be kept", status.Code(err), code)
+			}
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	ctx := context.Background()
+
+	if err := ctxgrpc.FromStatus(ctx, "/m", nil); err != nil {
+		t.Error("nil should stay nil", err)
+	}
+	if err := ctxgrpc.FromStatus(ctx, "/m", io.EOF); err != io.EOF {
+		t.Error("io.EOF should not be wrapped", err)
+	}
+	plain := errors.New("plain")
+	if err := ctxgrpc.FromStatus(ctx, "/m", plain); err != plain {
+		t.Error("non status errors should not be wrapped", err)
+	}
+
+	fields := ctxerr.AllFields(ctxgrpc.FromStatus(ctx, "/m", status.Error(codes.Unavailable, "down")))
+	if fields[ctxerr.FieldKeyCode] != codes.Unavailable.String() {
+		t.Error("code should default to the gRPC code", fields)
+	}
+	if fields[ctxerr.FieldKeyStatusCode] != http.StatusServiceUnavailable {
+		t.Error("status code did not match", fields)
+	}
+}
+
+func TestHTTPFromCode(t *testing.T) {
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.Unauthenticated, codes.PermissionDenied, codes.NotFound,
+		codes.AlreadyExists, codes.ResourceExhausted, codes.Unimplemented, codes.Unavailable, codes.DeadlineExceeded} {
+		if out := ctxgrpc.CodeFromHTTP(ctxgrpc.HTTPFromCode(code)); out != code {
+			t.Error("code did not round trip", code, out)
+		}
+	}
+	if out := ctxgrpc.HTTPFromCode(codes.DataLoss); out != http.StatusInternalServerError {
+		t.Error("status code did not match", out)
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/mvndaai/ctxerrhelper/grpc
+
+go 1.22
+
+require (
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a
+	google.golang.org/grpc v1.70.0
+)
+
+require (
+	golang.org/x/net v0.32.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+)
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+)
@@ -0,0 +1,216 @@
+/*
+Package grpc has interceptors to use ctxerr with gRPC (https://grpc.io).
+
+	import ctxgrpc "github.com/mvndaai/ctxerrhelper/grpc"
+
+	func main() {
+		conf := ctxgrpc.ServerConfig{ShowMessage: config.ShowMessage, ShowFields: config.ShowFields}
+		s := grpc.NewServer(
+			grpc.UnaryInterceptor(conf.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(conf.StreamServerInterceptor()),
+		)
+		...
+	}
+
+On the calling side the client interceptors convert status errors back into ctxerr errors
+
+	conn, err := grpc.NewClient(target,
+		grpc.WithUnaryInterceptor(ctxgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(ctxgrpc.StreamClientInterceptor()),
+	)
+*/
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID is the metadata key the request ID is read from
+const MetadataRequestID = "x-request-id"
+
+// ServerConfig configures how errors returned by gRPC handlers are converted into a status
+type ServerConfig struct {
+	// ShowMessage uses the error message as the status message. Defaults to the ctxerr code
+	ShowMessage bool
+	// ShowFields adds the ctxerr fields to the status as the metadata of an errdetails.ErrorInfo
+	ShowFields bool
+	// Domain is the errdetails.ErrorInfo domain, like the name of the service
+	Domain string
+	// Codes maps ctxerr codes to gRPC codes and takes precedence over a wrapped status and the status code field. codes.OK is ignored
+	Codes map[string]codes.Code
+	// SkipHandle tells if ctxerr.Handle should not be called, like not sending NotFound errors to slack
+	SkipHandle func(err error, code codes.Code) bool
+	// LogError is a way to log an error not using ctxerr.Handle to avoid circular errors
+	LogError func(error)
+}
+
+// UnaryServerInterceptor sets request fields on the context, calls ctxerr.Handle on errors returned by the handler
+// and converts them into a gRPC status with Status
+func (c ServerConfig) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(RequestFields(ctx, info.FullMethod), req)
+		if err != nil {
+			return resp, c.handle(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor sets request fields on the stream context, calls ctxerr.Handle on errors returned by the handler
+// and converts them into a gRPC status with Status
+func (c ServerConfig) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ws := &serverStream{ServerStream: ss, ctx: RequestFields(ss.Context(), info.FullMethod)}
+		if err := handler(srv, ws); err != nil {
+			return c.handle(err)
+		}
+		return nil
+	}
+}
+
+// serverStream replaces the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (c ServerConfig) handle(err error) error {
+	st := c.Status(err)
+	if c.SkipHandle == nil || !c.SkipHandle(err, st.Code()) {
+		ctxerr.Handle(err)
+	}
+	return st.Err()
+}
+
+// Status converts an error into a gRPC status.
+// Errors that already are a gRPC status are returned as is. Otherwise the code is chosen from, in order,
+// the Codes map, a wrapped gRPC status, the ctxerr status code field and context errors.
+// A wrapped status, like an error from a call using the client interceptors, keeps its original code through
+// the service even though FromStatus also sets a status code field. Use the Codes map to change it.
+func (c ServerConfig) Status(err error) *status.Status {
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return status.Convert(err)
+	}
+
+	_, response := ctxhttp.StatusCodeAndResponse(err, c.ShowMessage, c.ShowFields)
+	message := response.Error.Code
+	if c.ShowMessage {
+		message = response.Error.Message
+	}
+	st := status.New(c.code(err, response.Error.Code), message)
+
+	if response.Error.Code == "" && len(response.Error.Fields) == 0 {
+		return st
+	}
+	info := &errdetails.ErrorInfo{Reason: response.Error.Code, Domain: c.Domain}
+	if len(response.Error.Fields) > 0 {
+		info.Metadata = metadataFields(response.Error.Fields)
+	}
+	withDetails, detailsErr := st.WithDetails(info)
+	if detailsErr != nil {
+		if c.LogError != nil {
+			c.LogError(detailsErr)
+		}
+		return st
+	}
+	return withDetails
+}
+
+// code chooses the gRPC code of an error. It is never codes.OK because that would send the error as a success
+func (c ServerConfig) code(err error, ctxerrCode string) codes.Code {
+	if code, ok := c.Codes[ctxerrCode]; ok && ctxerrCode != "" && code != codes.OK {
+		return code
+	}
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) && se.GRPCStatus().Code() != codes.OK {
+		return se.GRPCStatus().Code()
+	}
+	if statusCode, ok := ctxerrfields.ErrorStatusCode(err); ok {
+		return CodeFromHTTP(statusCode)
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Unknown
+}
+
+// metadataFields converts fields with ctxerrfields.String to the string values errdetails.ErrorInfo metadata allows
+func metadataFields(fields map[string]any) map[string]string {
+	m := make(map[string]string, len(fields))
+	for k, v := range fields {
+		m[k] = ctxerrfields.String(v)
+	}
+	return m
+}
+
+// CodeFromHTTP converts an http status code of an error into the gRPC code with the closest meaning.
+// Status codes below 400 are codes.Unknown because an error must never be sent as codes.OK
+func CodeFromHTTP(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // Client Closed Request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case statusCode >= 400 && statusCode < 500:
+		return codes.FailedPrecondition
+	case statusCode >= 500:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// RequestFields sets the full gRPC method, the peer address and the request ID from the incoming metadata
+// as ctxerr fields on the context. The interceptors call it for every request.
+func RequestFields(ctx context.Context, fullMethod string) context.Context {
+	fields := map[string]interface{}{
+		ctxerrfields.FieldKeyGRPCMethod: fullMethod,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields[ctxerrfields.FieldKeyGRPCPeer] = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(MetadataRequestID); len(v) > 0 && v[0] != "" {
+			fields[ctxerrfields.FieldKeyRequestID] = v[0]
+		}
+	}
+	return ctxerr.SetFields(ctx, fields)
+}
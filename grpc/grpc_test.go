@@ -0,0 +1,276 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxgrpc "github.com/mvndaai/ctxerrhelper/grpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer returns the error from toErr for Check and Watch
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	toErr  func(context.Context) error
+	fields map[string]interface{}
+}
+
+func (s *healthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.fields = ctxerr.Fields(ctx)
+	if err := s.toErr(ctx); err != nil {
+		return nil, err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, ss grpc_health_v1.Health_WatchServer) error {
+	s.fields = ctxerr.Fields(ss.Context())
+	return s.toErr(ss.Context())
+}
+
+func newClient(t *testing.T, conf ctxgrpc.ServerConfig, srv grpc_health_v1.HealthServer, opts ...grpc.DialOption) grpc_health_v1.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(conf.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(conf.StreamServerInterceptor()),
+	)
+	grpc_health_v1.RegisterHealthServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatal("could not dial", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func errorInfo(t *testing.T, st *status.Status) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatal("status did not have error info", st.Details())
+	return nil
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name            string
+		conf            ctxgrpc.ServerConfig
+		toErr           func(context.Context) error
+		expectedCode    codes.Code
+		expectedMessage string
+	}{
+		{
+			name: "status code",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound, "message")
+			},
+			expectedCode:    codes.NotFound,
+			expectedMessage: "code",
+		},
+		{
+			name: "show message",
+			conf: ctxgrpc.ServerConfig{ShowMessage: true},
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusBadRequest, "message")
+			},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "message",
+		},
+		{
+			name: "codes map",
+			conf: ctxgrpc.ServerConfig{Codes: map[string]codes.Code{"code": codes.Aborted}},
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusBadRequest, "message")
+			},
+			expectedCode:    codes.Aborted,
+			expectedMessage: "code",
+		},
+		{
+			name: "wrapped status",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.Wrap(ctx, status.Error(codes.Unavailable, "down"), "code", "message")
+			},
+			expectedCode:    codes.Unavailable,
+			expectedMessage: "code",
+		},
+		{
+			name: "status",
+			toErr: func(ctx context.Context) error {
+				return status.Error(codes.ResourceExhausted, "slow down")
+			},
+			expectedCode:    codes.ResourceExhausted,
+			expectedMessage: "slow down",
+		},
+		{
+			name: "deadline",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.Wrap(ctx, context.DeadlineExceeded, "code")
+			},
+			expectedCode:    codes.DeadlineExceeded,
+			expectedMessage: "code",
+		},
+		{
+			name: "success status code",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusOK, "message")
+			},
+			expectedCode:    codes.Unknown,
+			expectedMessage: "code",
+		},
+		{
+			name: "codes map ok",
+			conf: ctxgrpc.ServerConfig{Codes: map[string]codes.Code{"code": codes.OK}},
+			toErr: func(ctx context.Context) error {
+				return ctxerr.New(ctx, "code", "message")
+			},
+			expectedCode:    codes.Unknown,
+			expectedMessage: "code",
+		},
+		{
+			name: "go error",
+			toErr: func(ctx context.Context) error {
+				return errors.New("message")
+			},
+			expectedCode:    codes.Unknown,
+			expectedMessage: "",
+		},
+	}
+
+	var handled error
+	ctxerr.AddHandleHook(func(err error) { handled = err })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			client := newClient(t, tt.conf, &healthServer{toErr: tt.toErr})
+
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+			st := status.Convert(err)
+			if st.Code() != tt.expectedCode {
+				t.Error("code did not match", st.Code(), tt.expectedCode)
+			}
+			if st.Message() != tt.expectedMessage {
+				t.Error("message did not match", st.Message(), tt.expectedMessage)
+			}
+			if handled == nil {
+				t.Error("error was not handled")
+			}
+		})
+	}
+}
+
+func TestServerFields(t *testing.T) {
+	conf := ctxgrpc.ServerConfig{ShowFields: true, Domain: "example.com"}
+	srv := &healthServer{toErr: func(ctx context.Context) error {
+		ctx = ctxerr.SetFields(ctx, map[string]interface{}{"str": "a", "int": 1})
+		return ctxerr.NewHTTP(ctx, "code", "", http.StatusConflict, "message")
+	}}
+	client := newClient(t, conf, srv)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), ctxgrpc.MetadataRequestID, "request-id")
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+	st := status.Convert(err)
+	if st.Code() != codes.AlreadyExists {
+		t.Error("code did not match", st.Code())
+	}
+	info := errorInfo(t, st)
+	if info.Reason != "code" || info.Domain != "example.com" {
+		t.Error("error info did not match", info)
+	}
+	expected := map[string]string{
+		"str":                           "a",
+		"int":                           "1",
+		ctxerrfields.FieldKeyGRPCMethod: "/grpc.health.v1.Health/Check",
+		ctxerrfields.FieldKeyRequestID:  "request-id",
+	}
+	for k, v := range expected {
+		if info.Metadata[k] != v {
+			t.Errorf("metadata %s did not match [%v] [%v]", k, info.Metadata[k], v)
+		}
+	}
+	if _, ok := info.Metadata[ctxerrfields.FieldKeyGRPCPeer]; !ok {
+		t.Error("peer should be a field", info.Metadata)
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	srv := &healthServer{toErr: func(ctx context.Context) error {
+		return ctxerr.NewHTTP(ctx, "code", "", http.StatusForbidden, "message")
+	}}
+	client := newClient(t, ctxgrpc.ServerConfig{}, srv)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal("could not watch", err)
+	}
+	_, err = stream.Recv()
+
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Error("code did not match", st.Code())
+	}
+	if v := srv.fields[ctxerrfields.FieldKeyGRPCMethod]; v != "/grpc.health.v1.Health/Watch" {
+		t.Error("method field did not match", v)
+	}
+}
+
+func TestSkipHandle(t *testing.T) {
+	handled := false
+	ctxerr.AddHandleHook(func(error) { handled = true })
+
+	conf := ctxgrpc.ServerConfig{SkipHandle: func(_ error, code codes.Code) bool { return code == codes.NotFound }}
+	client := newClient(t, conf, &healthServer{toErr: func(ctx context.Context) error {
+		return ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound)
+	}})
+
+	_, _ = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if handled {
+		t.Error("error should not be handled")
+	}
+}
+
+func TestCodeFromHTTP(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   codes.Code
+	}{
+		{statusCode: http.StatusUnauthorized, expected: codes.Unauthenticated},
+		{statusCode: http.StatusTooManyRequests, expected: codes.ResourceExhausted},
+		{statusCode: http.StatusTeapot, expected: codes.FailedPrecondition},
+		{statusCode: http.StatusBadGateway, expected: codes.Internal},
+		{statusCode: http.StatusServiceUnavailable, expected: codes.Unavailable},
+		{statusCode: http.StatusOK, expected: codes.Unknown},
+		{statusCode: http.StatusFound, expected: codes.Unknown},
+		{statusCode: 0, expected: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		if out := ctxgrpc.CodeFromHTTP(tt.statusCode); out != tt.expected {
+			t.Error("code did not match", tt.statusCode, out, tt.expected)
+		}
+	}
+}
@@ -3,7 +3,8 @@ module github.com/mvndaai/ctxerrhelper/logrus
 go 1.20
 
 require (
-	github.com/mvndaai/ctxerr v0.12.0
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
 	github.com/sirupsen/logrus v1.9.3
 )
 
@@ -11,3 +12,8 @@ require (
 	github.com/stretchr/testify v1.8.1 // indirect
 	golang.org/x/sys v0.10.0 // indirect
 )
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+)
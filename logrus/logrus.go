@@ -1,24 +1,66 @@
 package ctxerrlogrus
 
 import (
+	"strconv"
+
 	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
 	"github.com/sirupsen/logrus"
 )
 
 // NewContextHook creates a logrus hook that can be used to add ctxerr fields to logrus entries with a context.
 func NewContextHook() *ContextHook { return &ContextHook{} }
 
+// ConflictStrategy decides what happens when a ctxerr field key already exists in the entry.Data map
+type ConflictStrategy int
+
+const (
+	// ConflictStrategyOverwrite replaces the existing entry value with the ctxerr value
+	ConflictStrategyOverwrite ConflictStrategy = iota
+	// ConflictStrategyKeep keeps the existing entry value and drops the ctxerr value
+	ConflictStrategyKeep
+	// ConflictStrategyPrefix adds the ctxerr value under the key with ConflictPrefix prepended
+	ConflictStrategyPrefix
+	// ConflictStrategySuffix adds the ctxerr value under the key with ConflictSuffixSeparator and the first free counter appended
+	ConflictStrategySuffix
+	// ConflictStrategyMerge replaces the existing entry value with a slice of the existing and ctxerr values.
+	// An existing []interface{}, like one from an earlier merge, has the ctxerr value appended instead of being nested
+	ConflictStrategyMerge
+)
+
 // ContextHook implements logrus.Hook
 type ContextHook struct {
 	LogLevels []logrus.Level
+	// Selection chooses which context fields are added. Defaults to all fields
+	Selection ctxerrfields.Selection
+	// Conflict is the strategy used when a key already exists in the entry.Data map
+	Conflict ConflictStrategy
+	// ConflictPrefix will be prepended to the key when using ConflictStrategyPrefix. Defaults to DefaultConflictPrefix
+	ConflictPrefix string
+	// ConflictSuffixSeparator is put between the key and counter when using ConflictStrategySuffix. Defaults to DefaultConflictSuffixSeparator
+	ConflictSuffixSeparator string
+
 	// PrependConflicts will prepend the key if it already exists in the entry.Data map
+	//
+	// Deprecated: use Conflict with ConflictStrategyPrefix
 	PrependConflicts bool
 	// ConflitPrefix will be prepended to the key if it already exists in the entry.Data map if prepend is enabled
+	//
+	// Deprecated: use ConflictPrefix
 	ConflitPrefix string
 }
 
+const (
+	// DefaultConflictPrefix is the default prefix for keys that already exist on the entry when using ConflictStrategyPrefix
+	DefaultConflictPrefix = "ctxerr."
+	// DefaultConflictSuffixSeparator is the default separator between a key and its counter when using ConflictStrategySuffix
+	DefaultConflictSuffixSeparator = "_"
+)
+
 // DefaultConflitPrefix is the default prefix for keys that already exist on the entry when "PrependConflicts" enabled
-const DefaultConflitPrefix = "ctxerr."
+//
+// Deprecated: use DefaultConflictPrefix
+const DefaultConflitPrefix = DefaultConflictPrefix
 
 // Levels returns the log levels that this hook is enabled for
 func (hook ContextHook) Levels() []logrus.Level {
@@ -30,15 +72,63 @@ func (hook ContextHook) Levels() []logrus.Level {
 
 // Fire adds ctxerr fields to the logrus entry
 func (hook ContextHook) Fire(entry *logrus.Entry) error {
-	fields := ctxerr.Fields(entry.Context)
+	fields := hook.Selection.Select(ctxerr.Fields(entry.Context))
 	for k, v := range fields {
-		if _, ok := entry.Data[k]; ok && hook.PrependConflicts {
-			if hook.ConflitPrefix == "" {
-				hook.ConflitPrefix = DefaultConflitPrefix
+		v = ctxerrfields.Value(v)
+		existing, ok := entry.Data[k]
+		if !ok {
+			entry.Data[k] = v
+			continue
+		}
+
+		switch hook.strategy() {
+		case ConflictStrategyKeep:
+		case ConflictStrategyPrefix:
+			entry.Data[hook.prefix()+k] = v
+		case ConflictStrategySuffix:
+			entry.Data[hook.suffixedKey(entry.Data, k)] = v
+		case ConflictStrategyMerge:
+			if merged, ok := existing.([]interface{}); ok {
+				entry.Data[k] = append(merged[:len(merged):len(merged)], v)
+			} else {
+				entry.Data[k] = []interface{}{existing, v}
 			}
-			k = hook.ConflitPrefix + k
+		default:
+			entry.Data[k] = v
 		}
-		entry.Data[k] = v
 	}
 	return nil
 }
+
+// strategy returns the conflict strategy taking the deprecated PrependConflicts into account
+func (hook ContextHook) strategy() ConflictStrategy {
+	if hook.Conflict == ConflictStrategyOverwrite && hook.PrependConflicts {
+		return ConflictStrategyPrefix
+	}
+	return hook.Conflict
+}
+
+// prefix returns the prefix used by ConflictStrategyPrefix without modifying the hook
+func (hook ContextHook) prefix() string {
+	if hook.ConflictPrefix != "" {
+		return hook.ConflictPrefix
+	}
+	if hook.ConflitPrefix != "" {
+		return hook.ConflitPrefix
+	}
+	return DefaultConflictPrefix
+}
+
+// suffixedKey returns the key with the lowest counter suffix that is not already in data
+func (hook ContextHook) suffixedKey(data logrus.Fields, key string) string {
+	sep := hook.ConflictSuffixSeparator
+	if sep == "" {
+		sep = DefaultConflictSuffixSeparator
+	}
+	for i := 1; ; i++ {
+		k := key + sep + strconv.Itoa(i)
+		if _, ok := data[k]; !ok {
+			return k
+		}
+	}
+}
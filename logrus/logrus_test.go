@@ -3,10 +3,12 @@ package ctxerrlogrus_test
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
 	ctxerrlogrus "github.com/mvndaai/ctxerrhelper/logrus"
 	"github.com/sirupsen/logrus"
 )
@@ -74,6 +76,39 @@ func TestHookWithLogLevels(t *testing.T) {
 	}
 }
 
+func TestHookUnmarshalableField(t *testing.T) {
+	lg := logrus.New()
+	lg.SetFormatter(&logrus.JSONFormatter{})
+	sb := &strings.Builder{}
+	lg.Out = sb
+
+	lg.AddHook(ctxerrlogrus.NewContextHook())
+
+	ctx := ctxerr.SetField(context.Background(), "func", func() {})
+	lg.WithContext(ctx).Info("msg")
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(sb.String()), &m); err != nil {
+		t.Error("could not unmarshall json", err, sb.String())
+	}
+	if m["func"] != "func()" {
+		t.Error("func field should be rendered as its type", m)
+	}
+}
+
+func TestHookSelection(t *testing.T) {
+	hook := ctxerrlogrus.ContextHook{Selection: ctxerrfields.Selection{Include: []string{"foo"}}}
+
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"foo": "bar", "baz": "qux"})
+	entry := logrus.NewEntry(logrus.New()).WithContext(ctx)
+	if err := hook.Fire(entry); err != nil {
+		t.Fatal("could not fire hook", err)
+	}
+	if !reflect.DeepEqual(map[string]interface{}(entry.Data), map[string]interface{}{"foo": "bar"}) {
+		t.Error("only selected fields should be added", entry.Data)
+	}
+}
+
 func TestHookWithConflictPrefix(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -122,6 +157,97 @@ func TestHookWithConflictPrefix(t *testing.T) {
 	}
 }
 
+func TestHookConflictStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		hook     ctxerrlogrus.ContextHook
+		data     logrus.Fields
+		expected map[string]interface{}
+	}{
+		{
+			name:     "overwrite",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyOverwrite},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			name:     "keep",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyKeep},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "baz"},
+		},
+		{
+			name:     "prefix default",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyPrefix},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "baz", ctxerrlogrus.DefaultConflictPrefix + "foo": "bar"},
+		},
+		{
+			name:     "prefix set",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyPrefix, ConflictPrefix: "a."},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "baz", "a.foo": "bar"},
+		},
+		{
+			name:     "prefix deprecated field",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyPrefix, ConflitPrefix: "b."},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "baz", "b.foo": "bar"},
+		},
+		{
+			name:     "suffix",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategySuffix},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": "baz", "foo_1": "bar"},
+		},
+		{
+			name:     "suffix counter",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategySuffix, ConflictSuffixSeparator: "#"},
+			data:     logrus.Fields{"foo": "baz", "foo#1": "qux"},
+			expected: map[string]interface{}{"foo": "baz", "foo#1": "qux", "foo#2": "bar"},
+		},
+		{
+			name:     "merge",
+			hook:     ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyMerge},
+			data:     logrus.Fields{"foo": "baz"},
+			expected: map[string]interface{}{"foo": []interface{}{"baz", "bar"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+			entry := logrus.NewEntry(logrus.New()).WithContext(ctx).WithFields(tt.data)
+
+			if err := tt.hook.Fire(entry); err != nil {
+				t.Fatal("could not fire hook", err)
+			}
+			if !reflect.DeepEqual(map[string]interface{}(entry.Data), tt.expected) {
+				t.Errorf("data did not match\n%v\n%v", entry.Data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHookConflictMergeMultiple(t *testing.T) {
+	hook := ctxerrlogrus.ContextHook{Conflict: ctxerrlogrus.ConflictStrategyMerge}
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	entry := logrus.NewEntry(logrus.New()).WithContext(ctx).WithField("foo", "baz")
+
+	if err := hook.Fire(entry); err != nil {
+		t.Fatal("could not fire hook", err)
+	}
+	entry.Context = ctxerr.SetField(ctx, "foo", "qux")
+	if err := hook.Fire(entry); err != nil {
+		t.Fatal("could not fire hook", err)
+	}
+
+	expected := []interface{}{"baz", "bar", "qux"}
+	if !reflect.DeepEqual(entry.Data["foo"], expected) {
+		t.Errorf("merged value did not match\n%v\n%v", entry.Data["foo"], expected)
+	}
+}
+
 func ExampleNewContextHook() {
 	lg := logrus.New()
 	lg.AddHook(ctxerrlogrus.NewContextHook())
@@ -0,0 +1,144 @@
+package nethttp
+
+import (
+	"net/http"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+// Renderer writes the error response for a media type. C is the request context of a framework, like echo.Context
+type Renderer[C any] func(c C, statusCode int, response ctxhttp.ErrorResponse) error
+
+// Options configure the error handlers of the framework packages. C is the request context of the framework
+type Options[C any] struct {
+	// ShowMessage adds the error message to the response
+	ShowMessage bool
+	// ShowFields adds the ctxerr fields to the response
+	ShowFields bool
+	// Renderers write the response for the media type that best matches the Accept header. Defaults to the framework renderers
+	Renderers map[string]Renderer[C]
+	// DefaultRenderer is used when no renderer matches the Accept header. Defaults to the framework JSON renderer
+	DefaultRenderer Renderer[C]
+	// LogError is used when writing the response fails. Defaults to the framework logger
+	LogError func(error)
+	// SkipHandle tells if ctxerr.Handle should not be called, like not sending 404s to slack
+	SkipHandle func(err error, statusCode int) bool
+	// StatusCode maps the error and the status code that would be used to the status code of the response
+	StatusCode func(err error, statusCode int) int
+	// AllowedFields limits the fields shown in the response to these keys. Defaults to all fields when ShowFields is true
+	AllowedFields []string
+	// Headers returns headers to add to the response, like X-Trace-Id
+	Headers func(c C, response ctxhttp.ErrorResponse) map[string]string
+	// ModifyResponse can change the response before it is written
+	ModifyResponse func(err error, c C, response *ctxhttp.ErrorResponse)
+}
+
+// Framework adapts the request context of a framework to ErrorHandler
+type Framework[C any] struct {
+	// Request returns the request of c
+	Request func(c C) *http.Request
+	// Header returns the response header of c
+	Header func(c C) http.Header
+	// Committed tells if the response of c was already written
+	Committed func(c C) bool
+	// NoContent writes the status code without a body
+	NoContent func(c C, statusCode int) error
+	// StatusCodeAndResponse returns the status code and response of an error, including the framework's own errors
+	StatusCodeAndResponse func(err error, c C, showMessage, showFields bool) (int, ctxhttp.ErrorResponse)
+	// LogError logs write failures when Options.LogError is nil
+	LogError func(c C, err error)
+	// Renderers are used when Options.Renderers is nil
+	Renderers map[string]Renderer[C]
+	// DefaultRenderer is used when Options.DefaultRenderer is nil
+	DefaultRenderer Renderer[C]
+}
+
+// ErrorHandler returns the error handler flow shared by the framework packages.
+// The error is always handled but nothing is written if the response was already committed and HEAD requests get no body.
+// Other responses are rendered by negotiating the request Accept header against the renderers.
+func ErrorHandler[C any](o Options[C], f Framework[C]) func(err error, c C) {
+	renderers := o.Renderers
+	if renderers == nil {
+		renderers = f.Renderers
+	}
+	defaultRenderer := o.DefaultRenderer
+	if defaultRenderer == nil {
+		defaultRenderer = f.DefaultRenderer
+	}
+	logError := func(c C, err error) {
+		if o.LogError != nil {
+			o.LogError(err)
+			return
+		}
+		f.LogError(c, err)
+	}
+
+	return func(err error, c C) {
+		statusCode, response := f.StatusCodeAndResponse(err, c, o.ShowMessage, o.ShowFields)
+		if o.StatusCode != nil {
+			statusCode = o.StatusCode(err, statusCode)
+		}
+
+		if o.SkipHandle == nil || !o.SkipHandle(err, statusCode) {
+			ctxerr.Handle(err)
+		}
+		if f.Committed(c) {
+			return
+		}
+
+		if o.AllowedFields != nil {
+			response.Error.Fields = allowedFields(response.Error.Fields, o.AllowedFields)
+		}
+		if o.ModifyResponse != nil {
+			o.ModifyResponse(err, c, &response)
+		}
+		if o.Headers != nil {
+			for k, v := range o.Headers(c, response) {
+				f.Header(c).Set(k, v)
+			}
+		}
+
+		r := f.Request(c)
+		if r.Method == http.MethodHead {
+			if err := f.NoContent(c, statusCode); err != nil {
+				logError(c, err)
+			}
+			return
+		}
+
+		render, ok := Negotiate(r.Header.Get("Accept"), renderers)
+		if !ok || render == nil {
+			render = defaultRenderer
+		}
+		f.Header(c).Add("Vary", "Accept")
+		if err := render(c, statusCode, response); err != nil {
+			logError(c, err)
+		}
+	}
+}
+
+func allowedFields(fields map[string]interface{}, allowed []string) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	m := map[string]interface{}{}
+	for _, k := range allowed {
+		if v, ok := fields[k]; ok {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// StatusCodeAndResponse is ctxerr/http.StatusCodeAndResponse with sanitized fields and, when the error has none,
+// the trace ID of the request
+func StatusCodeAndResponse(err error, r *http.Request, showMessage, showFields bool) (int, ctxhttp.ErrorResponse) {
+	statusCode, response := ctxhttp.StatusCodeAndResponse(err, showMessage, showFields)
+	response.Error.Fields = ctxerrfields.Sanitize(response.Error.Fields)
+	if response.Error.TraceID == "" {
+		response.Error.TraceID = ctxhttp.TraceID(r.Context())
+	}
+	return statusCode, response
+}
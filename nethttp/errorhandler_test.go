@@ -0,0 +1,298 @@
+package nethttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+// testContext is a minimal framework request context
+type testContext struct {
+	r         *http.Request
+	rec       *httptest.ResponseRecorder
+	committed bool
+	logged    error
+}
+
+func renderJSON(c *testContext, statusCode int, response ctxhttp.ErrorResponse) error {
+	c.rec.Header().Set("Content-Type", "application/json")
+	c.rec.WriteHeader(statusCode)
+	return json.NewEncoder(c.rec).Encode(response)
+}
+
+func renderText(c *testContext, statusCode int, response ctxhttp.ErrorResponse) error {
+	c.rec.Header().Set("Content-Type", "text/plain")
+	c.rec.WriteHeader(statusCode)
+	_, err := fmt.Fprint(c.rec, ctxnethttp.Text(statusCode, response))
+	return err
+}
+
+var testFramework = ctxnethttp.Framework[*testContext]{
+	Request:   func(c *testContext) *http.Request { return c.r },
+	Header:    func(c *testContext) http.Header { return c.rec.Header() },
+	Committed: func(c *testContext) bool { return c.committed },
+	NoContent: func(c *testContext, statusCode int) error {
+		c.rec.WriteHeader(statusCode)
+		return nil
+	},
+	StatusCodeAndResponse: func(err error, c *testContext, showMessage, showFields bool) (int, ctxhttp.ErrorResponse) {
+		return ctxnethttp.StatusCodeAndResponse(err, c.r, showMessage, showFields)
+	},
+	LogError:        func(c *testContext, err error) { c.logged = err },
+	Renderers:       map[string]ctxnethttp.Renderer[*testContext]{"application/json": renderJSON, "text/plain": renderText},
+	DefaultRenderer: renderJSON,
+}
+
+func newTestContext(method, accept string) *testContext {
+	r := httptest.NewRequest(method, "/", nil)
+	r.Header.Set("Accept", accept)
+	return &testContext{r: r, rec: httptest.NewRecorder()}
+}
+
+func TestErrorHandler(t *testing.T) {
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"public": "a", "private": "b"})
+
+	tests := []struct {
+		name               string
+		options            ctxnethttp.Options[*testContext]
+		err                error
+		expectedHandled    bool
+		expectedStatusCode int
+		expectedFields     map[string]interface{}
+		expectedHeaders    map[string]string
+		expectedMessage    string
+	}{
+		{
+			name:               "skip handle",
+			options:            ctxnethttp.Options[*testContext]{SkipHandle: func(_ error, statusCode int) bool { return statusCode == http.StatusNotFound }},
+			err:                ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound),
+			expectedHandled:    false,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "do not skip handle",
+			options:            ctxnethttp.Options[*testContext]{SkipHandle: func(_ error, statusCode int) bool { return statusCode == http.StatusNotFound }},
+			err:                ctxerr.New(ctx, "code"),
+			expectedHandled:    true,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "status code",
+			options: ctxnethttp.Options[*testContext]{StatusCode: func(_ error, statusCode int) int {
+				if statusCode == http.StatusInternalServerError {
+					return http.StatusServiceUnavailable
+				}
+				return statusCode
+			}},
+			err:                ctxerr.New(ctx, "code"),
+			expectedHandled:    true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "allowed fields",
+			options:            ctxnethttp.Options[*testContext]{ShowFields: true, AllowedFields: []string{"public"}},
+			err:                ctxerr.New(ctx, "code"),
+			expectedHandled:    true,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedFields:     map[string]interface{}{"public": "a"},
+		},
+		{
+			name: "headers",
+			options: ctxnethttp.Options[*testContext]{Headers: func(_ *testContext, response ctxhttp.ErrorResponse) map[string]string {
+				return map[string]string{"X-Error-Code": response.Error.Code}
+			}},
+			err:                ctxerr.New(ctx, "code"),
+			expectedHandled:    true,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedHeaders:    map[string]string{"X-Error-Code": "code", "Vary": "Accept"},
+		},
+		{
+			name: "modify response",
+			options: ctxnethttp.Options[*testContext]{ModifyResponse: func(_ error, _ *testContext, response *ctxhttp.ErrorResponse) {
+				response.Error.Message = "modified"
+			}},
+			err:                ctxerr.New(ctx, "code"),
+			expectedHandled:    true,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedMessage:    "modified",
+		},
+	}
+
+	var handled error
+	ctxerr.AddHandleHook(func(err error) { handled = err })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			c := newTestContext(http.MethodGet, "")
+
+			ctxnethttp.ErrorHandler(tt.options, testFramework)(tt.err, c)
+
+			if (handled == tt.err) != tt.expectedHandled {
+				t.Error("handled did not match", handled, tt.expectedHandled)
+			}
+			if c.rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", c.rec.Code, tt.expectedStatusCode)
+			}
+			for k, v := range tt.expectedHeaders {
+				if h := c.rec.Header().Get(k); h != v {
+					t.Errorf("header %s did not match [%s] [%s]", k, h, v)
+				}
+			}
+
+			var response ctxhttp.ErrorResponse
+			if err := json.Unmarshal(c.rec.Body.Bytes(), &response); err != nil {
+				t.Fatal("response did not marshall into JSON", err, c.rec.Body.String())
+			}
+			if tt.expectedFields != nil && !reflect.DeepEqual(response.Error.Fields, tt.expectedFields) {
+				t.Error("fields did not match", response.Error.Fields, tt.expectedFields)
+			}
+			if response.Error.Message != tt.expectedMessage {
+				t.Error("message did not match", response.Error.Message, tt.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerRenderers(t *testing.T) {
+	custom := func(c *testContext, statusCode int, response ctxhttp.ErrorResponse) error {
+		c.rec.WriteHeader(statusCode)
+		_, err := fmt.Fprintf(c.rec, "custom %s", response.Error.Code)
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		options  ctxnethttp.Options[*testContext]
+		accept   string
+		expected string
+	}{
+		{name: "framework renderer", accept: "text/plain", expected: "500 Internal Server Error\ncode: code\n"},
+		{name: "framework default", accept: "image/png", expected: `{"error":{"code":"code"}}` + "\n"},
+		{
+			name:     "option renderer",
+			options:  ctxnethttp.Options[*testContext]{Renderers: map[string]ctxnethttp.Renderer[*testContext]{"application/vnd.custom+json": custom}},
+			accept:   "application/vnd.custom+json",
+			expected: "custom code",
+		},
+		{
+			name:     "option default",
+			options:  ctxnethttp.Options[*testContext]{DefaultRenderer: custom},
+			accept:   "*/*",
+			expected: "custom code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestContext(http.MethodGet, tt.accept)
+			ctxnethttp.ErrorHandler(tt.options, testFramework)(ctxerr.New(c.r.Context(), "code"), c)
+
+			if v := c.rec.Body.String(); v != tt.expected {
+				t.Errorf("body did not match\n%q\n%q", v, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerCommitted(t *testing.T) {
+	c := newTestContext(http.MethodGet, "")
+	c.committed = true
+
+	var handled bool
+	ctxerr.AddHandleHook(func(err error) {
+		if err.Error() == "committed" {
+			handled = true
+		}
+	})
+
+	ctxnethttp.ErrorHandler(ctxnethttp.Options[*testContext]{}, testFramework)(ctxerr.New(c.r.Context(), "code", "committed"), c)
+
+	if !handled {
+		t.Error("error should be handled even when committed")
+	}
+	if c.rec.Body.Len() != 0 || len(c.rec.Header()) != 0 {
+		t.Error("committed response should not change", c.rec.Body.String(), c.rec.Header())
+	}
+}
+
+func TestErrorHandlerHead(t *testing.T) {
+	c := newTestContext(http.MethodHead, "")
+
+	ctxnethttp.ErrorHandler(ctxnethttp.Options[*testContext]{}, testFramework)(ctxerr.NewHTTP(c.r.Context(), "code", "", http.StatusNotFound), c)
+
+	if c.rec.Code != http.StatusNotFound {
+		t.Error("status code did not match", c.rec.Code)
+	}
+	if c.rec.Body.Len() != 0 {
+		t.Error("HEAD should not have a body", c.rec.Body.String())
+	}
+}
+
+func TestErrorHandlerLogError(t *testing.T) {
+	writeErr := errors.New("write")
+	failing := func(*testContext, int, ctxhttp.ErrorResponse) error { return writeErr }
+
+	c := newTestContext(http.MethodGet, "")
+	ctxnethttp.ErrorHandler(ctxnethttp.Options[*testContext]{DefaultRenderer: failing}, testFramework)(errors.New("err"), c)
+	if c.logged != writeErr {
+		t.Error("write error should be logged by the framework", c.logged)
+	}
+
+	var logged error
+	c = newTestContext(http.MethodGet, "")
+	ctxnethttp.ErrorHandler(ctxnethttp.Options[*testContext]{
+		DefaultRenderer: failing,
+		LogError:        func(err error) { logged = err },
+	}, testFramework)(errors.New("err"), c)
+	if logged != writeErr || c.logged != nil {
+		t.Error("write error should be logged by the option", logged, c.logged)
+	}
+}
+
+func TestStatusCodeAndResponse(t *testing.T) {
+	ctx := ctxerr.SetField(context.Background(), "func", func() {})
+	statusCode, response := ctxnethttp.StatusCodeAndResponse(ctxerr.NewHTTP(ctx, "code", "", http.StatusTeapot), httptest.NewRequest("GET", "/", nil), true, true)
+
+	if statusCode != http.StatusTeapot {
+		t.Error("status code did not match", statusCode)
+	}
+	if v := response.Error.Fields["func"]; v != "func()" {
+		t.Error("func field should be rendered as its type", v)
+	}
+}
+
+func TestFields(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set("User-Agent", "agent")
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: "GET",
+		ctxerrfields.FieldKeyHTTPRoute:  "/users/:id",
+		ctxerrfields.FieldKeyRequestID:  "request-id",
+		ctxerrfields.FieldKeyClientIP:   "10.0.0.1",
+		ctxerrfields.FieldKeyUserAgent:  "agent",
+	}
+	if fields := ctxnethttp.Fields(r, "/users/:id", "10.0.0.1", "request-id"); !reflect.DeepEqual(fields, expected) {
+		t.Error("fields did not match", fields)
+	}
+
+	r.Header.Del("User-Agent")
+	expected = map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: "GET",
+		ctxerrfields.FieldKeyClientIP:   "10.0.0.1",
+	}
+	if fields := ctxnethttp.Fields(r, "", "10.0.0.1", ""); !reflect.DeepEqual(fields, expected) {
+		t.Error("empty values should be left out", fields)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Package nethttp has functions to use ctxerr with net/http (https://pkg.go.dev/net/http) without a framework.
+
+	import ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+
+	func main() {
+		conf := ctxnethttp.Config{ShowMessage: config.ShowMessage, ShowFields: config.ShowFields}
+
+		mux := http.NewServeMux()
+		mux.Handle("/users", conf.Handler(func(w http.ResponseWriter, r *http.Request) error {
+			...
+		}))
+		http.ListenAndServe(":8080", ctxnethttp.RequestFields(conf.Recover(mux)))
+	}
+*/
+package nethttp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+const (
+	// HeaderXRequestID is the header the request ID is read from
+	HeaderXRequestID = "X-Request-ID"
+	// HeaderXRealIP is the header the client IP is read from before X-Forwarded-For and the remote address
+	HeaderXRealIP = "X-Real-IP"
+	// HeaderXForwardedFor is the header the client IP is read from before the remote address
+	HeaderXForwardedFor = "X-Forwarded-For"
+)
+
+// HandlerFunc is an http handler that returns an error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f and writes any returned error using the zero Config
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Config{}.Handler(f).ServeHTTP(w, r)
+}
+
+// Config configures how errors are written
+type Config struct {
+	// ShowMessage adds the error message to the response
+	ShowMessage bool
+	// ShowFields adds the ctxerr fields to the response
+	ShowFields bool
+	// LogError is a way to log an error not using ctxerr.Handle to avoid circular errors
+	LogError func(error)
+}
+
+// Handler adapts f to an http.Handler that writes any returned error with WriteError
+func (c Config) Handler(f HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := wrap(w)
+		if err := f(rw, r); err != nil {
+			c.WriteError(rw, r, err)
+		}
+	})
+}
+
+// WriteError calls ctxerr.Handle and writes the ctxerr/http response of the error with WriteResponse
+// so the Accept header chooses between JSON, problem details, HTML and text.
+// Nothing is written if the response writer came from this package and was already written to.
+func (c Config) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	ctxerr.Handle(err)
+	if wt, ok := w.(writeTracker); ok && wt.wasWritten() {
+		return
+	}
+
+	statusCode, response := StatusCodeAndResponse(err, r, c.ShowMessage, c.ShowFields)
+	if err := WriteResponse(w, r, statusCode, response); err != nil && c.LogError != nil {
+		c.LogError(err)
+	}
+}
+
+// writeTracker is implemented by the writers from wrap
+type writeTracker interface {
+	wasWritten() bool
+}
+
+// responseWriter tracks if anything was written so an error response is not written on top of it
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// wrap returns a writer that tracks if anything was written. The http.Flusher, http.Hijacker and io.ReaderFrom
+// interfaces of w are kept so streaming and websocket handlers still work.
+func wrap(w http.ResponseWriter) http.ResponseWriter {
+	if _, ok := w.(writeTracker); ok {
+		return w
+	}
+	rw := &responseWriter{ResponseWriter: w}
+	_, isFlusher := w.(http.Flusher)
+	_, isHijacker := w.(http.Hijacker)
+	_, isReaderFrom := w.(io.ReaderFrom)
+	f, h, r := flusher{rw}, hijacker{rw}, readerFrom{rw}
+
+	switch {
+	case isFlusher && isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, f, h, r}
+	case isFlusher && isHijacker:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+		}{rw, f, h}
+	case isFlusher && isReaderFrom:
+		return struct {
+			*responseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{rw, f, r}
+	case isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{rw, h, r}
+	case isFlusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+		}{rw, f}
+	case isHijacker:
+		return struct {
+			*responseWriter
+			http.Hijacker
+		}{rw, h}
+	case isReaderFrom:
+		return struct {
+			*responseWriter
+			io.ReaderFrom
+		}{rw, r}
+	}
+	return rw
+}
+
+func (rw *responseWriter) wasWritten() bool { return rw.written }
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	rw.written = true
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.written = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the original writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+type flusher struct{ *responseWriter }
+
+func (f flusher) Flush() {
+	f.written = true
+	f.ResponseWriter.(http.Flusher).Flush()
+}
+
+type hijacker struct{ *responseWriter }
+
+func (h hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.written = true
+	return h.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+type readerFrom struct{ *responseWriter }
+
+func (r readerFrom) ReadFrom(src io.Reader) (int64, error) {
+	r.written = true
+	return r.ResponseWriter.(io.ReaderFrom).ReadFrom(src)
+}
+
+// RequestFields is a middleware that sets the request method, request ID, client IP and user agent
+// as ctxerr fields on the request context so every error created in a handler carries them.
+func RequestFields(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ctxerr.SetFields(r.Context(), Fields(r, "", ClientIP(r), r.Header.Get(HeaderXRequestID)))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Fields returns the request fields set by the RequestFields middlewares of this and the framework packages:
+// the request method, client IP and user agent and the route and request ID when they are not empty
+func Fields(r *http.Request, route, clientIP, requestID string) map[string]interface{} {
+	fields := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: r.Method,
+		ctxerrfields.FieldKeyClientIP:   clientIP,
+	}
+	if route != "" {
+		fields[ctxerrfields.FieldKeyHTTPRoute] = route
+	}
+	if requestID != "" {
+		fields[ctxerrfields.FieldKeyRequestID] = requestID
+	}
+	if ua := r.UserAgent(); ua != "" {
+		fields[ctxerrfields.FieldKeyUserAgent] = ua
+	}
+	return fields
+}
+
+// ClientIP returns the IP from the X-Real-IP header, the first X-Forwarded-For address or the remote address
+func ClientIP(r *http.Request) string {
+	if ip := r.Header.Get(HeaderXRealIP); ip != "" {
+		return ip
+	}
+	if ips := r.Header.Get(HeaderXForwardedFor); ips != "" {
+		ip, _, _ := strings.Cut(ips, ",")
+		return strings.TrimSpace(ip)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// CodePanic is the ctxerr code of errors created by Recover
+const CodePanic = "panic"
+
+// Recover is a middleware that converts a panic with PanicError and writes it with WriteError.
+// http.ErrAbortHandler is panicked again so net/http can abort the response.
+func (c Config) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := wrap(w)
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			c.WriteError(rw, r, PanicError(r.Context(), rec))
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// PanicError converts a recovered value into a ctxerr error with the code CodePanic, status 500 and the panic stack
+// as a field. Recovered errors are wrapped. It is shared by the Recover middlewares of the framework packages.
+func PanicError(ctx context.Context, r interface{}) error {
+	ctx = ctxerr.SetField(ctx, ctxerrfields.FieldKeyPanicStack, string(debug.Stack()))
+	if err, ok := r.(error); ok {
+		return ctxerr.WrapHTTP(ctx, err, CodePanic, "", http.StatusInternalServerError, "panic")
+	}
+	return ctxerr.NewHTTP(ctx, CodePanic, "", http.StatusInternalServerError, fmt.Sprintf("panic: %v", r))
+}
@@ -0,0 +1,267 @@
+package nethttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+func TestHandler(t *testing.T) {
+	code := "code"
+	message := "message"
+
+	tests := []struct {
+		name               string
+		toErr              func(context.Context) error
+		expectedCode       string
+		expectedStatusCode int
+	}{
+		{
+			name: "ctxerr",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, code, "", http.StatusBadRequest, message)
+			},
+			expectedCode:       code,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "go error",
+			toErr: func(ctx context.Context) error {
+				return errors.New(message)
+			},
+			expectedCode:       "",
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	handled := false
+	ctxerr.AddHandleHook(func(_ error) { handled = true })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = false
+			conf := ctxnethttp.Config{ShowMessage: true, LogError: func(err error) { t.Error(err) }}
+			h := conf.Handler(func(w http.ResponseWriter, r *http.Request) error {
+				return tt.toErr(r.Context())
+			})
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if !handled {
+				t.Error("error not handled")
+			}
+			if rec.Code != tt.expectedStatusCode {
+				t.Error("status code did not match", rec.Code, tt.expectedStatusCode)
+			}
+
+			var response ctxhttp.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+			}
+			if v := response.Error.Code; v != tt.expectedCode {
+				t.Error("code did not match", v, tt.expectedCode)
+			}
+			if v := response.Error.Message; v != message {
+				t.Error("message did not match", v, message)
+			}
+		})
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	var h http.Handler = ctxnethttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return ctxerr.NewHTTP(r.Context(), "code", "", http.StatusNotFound, "message")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Error("status code did not match", rec.Code)
+	}
+	var response ctxhttp.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+	}
+	if v := response.Error.Message; v != "" {
+		t.Error("message should be hidden by default", v)
+	}
+}
+
+func TestHandlerWritten(t *testing.T) {
+	h := ctxnethttp.Config{}.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+		return errors.New("after write")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Error("written response should not change", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerFlushed(t *testing.T) {
+	h := ctxnethttp.Config{}.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.(http.Flusher).Flush()
+		return errors.New("after flush")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if !rec.Flushed || rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Error("flushed response should not change", rec.Flushed, rec.Code, rec.Body.String())
+	}
+}
+
+func TestOptionalInterfaces(t *testing.T) {
+	conf := ctxnethttp.Config{}
+	var flusher, hijacker, readerFrom bool
+	h := conf.Recover(conf.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		_, flusher = w.(http.Flusher)
+		_, hijacker = w.(http.Hijacker)
+		_, readerFrom = w.(io.ReaderFrom)
+		return nil
+	}))
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal("could not make request", err)
+	}
+	resp.Body.Close()
+
+	if !flusher || !hijacker || !readerFrom {
+		t.Error("optional interfaces should be kept", flusher, hijacker, readerFrom)
+	}
+}
+
+func TestHandlerHead(t *testing.T) {
+	h := ctxnethttp.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return ctxerr.NewHTTP(r.Context(), "code", "", http.StatusNotFound)
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Error("status code did not match", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Error("HEAD should not have a body", rec.Body.String())
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	var fields map[string]interface{}
+	h := ctxnethttp.RequestFields(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = ctxerr.AllFields(ctxerr.New(r.Context(), "code"))
+	}))
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set(ctxnethttp.HeaderXRequestID, "request-id")
+	req.Header.Set("User-Agent", "agent")
+	req.RemoteAddr = "10.0.0.1:1234"
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyHTTPMethod: "GET",
+		ctxerrfields.FieldKeyRequestID:  "request-id",
+		ctxerrfields.FieldKeyClientIP:   "10.0.0.1",
+		ctxerrfields.FieldKeyUserAgent:  "agent",
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{name: "remote address", expected: "192.0.2.1"},
+		{name: "real ip", headers: map[string]string{ctxnethttp.HeaderXRealIP: "10.0.0.1", ctxnethttp.HeaderXForwardedFor: "10.0.0.2"}, expected: "10.0.0.1"},
+		{name: "forwarded for", headers: map[string]string{ctxnethttp.HeaderXForwardedFor: "10.0.0.2, 10.0.0.3"}, expected: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if ip := ctxnethttp.ClientIP(req); ip != tt.expected {
+				t.Error("ip did not match", ip, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	conf := ctxnethttp.Config{ShowMessage: true, ShowFields: true}
+	h := conf.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") }))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Error("status code did not match", rec.Code)
+	}
+
+	var response ctxhttp.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal("response did not marshall into JSON", err, rec.Body.String())
+	}
+	if v := response.Error.Code; v != ctxnethttp.CodePanic {
+		t.Error("code did not match", v)
+	}
+	if v := response.Error.Message; v != "panic: boom" {
+		t.Error("message did not match", v)
+	}
+	if v, _ := response.Error.Fields[ctxerrfields.FieldKeyPanicStack].(string); !strings.Contains(v, "goroutine") {
+		t.Error("stack should be a field", v)
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	cause := errors.New("cause")
+	err := ctxnethttp.PanicError(context.Background(), cause)
+
+	if !errors.Is(err, cause) {
+		t.Error("recovered errors should be wrapped", err)
+	}
+	fields := ctxerr.AllFields(err)
+	if fields[ctxerr.FieldKeyCode] != ctxnethttp.CodePanic || fields[ctxerr.FieldKeyStatusCode] != http.StatusInternalServerError {
+		t.Error("code and status code did not match", fields)
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	h := ctxnethttp.Config{}.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) }))
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Error("http.ErrAbortHandler should be re-panicked", r)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
@@ -0,0 +1,222 @@
+package nethttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+)
+
+// MIMEApplicationProblemJSON is the RFC 7807 media type for problem details
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Negotiate returns the value in values for the media range in the Accept header with the highest quality.
+// False is returned when nothing matches or the best match is */* so the caller can use its default.
+// It is shared by the framework packages that negotiate their error responses.
+func Negotiate[V any](accept string, values map[string]V) (V, bool) {
+	for _, mediaRange := range parseAccept(accept) {
+		if mediaRange == "*/*" {
+			break
+		}
+		if v, ok := values[mediaRange]; ok {
+			return v, true
+		}
+		if strings.HasSuffix(mediaRange, "/*") {
+			prefix := strings.TrimSuffix(mediaRange, "*")
+			var matches []string
+			for mediaType := range values {
+				if strings.HasPrefix(mediaType, prefix) {
+					matches = append(matches, mediaType)
+				}
+			}
+			if len(matches) > 0 {
+				sort.Strings(matches)
+				return values[matches[0]], true
+			}
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// parseAccept returns the media ranges of an Accept header ordered by quality, dropping any with a quality of 0
+func parseAccept(accept string) []string {
+	type mediaRange struct {
+		value string
+		q     float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, mediaRange{value: value, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	values := make([]string, len(ranges))
+	for i, r := range ranges {
+		values[i] = r.value
+	}
+	return values
+}
+
+// Problem is an RFC 7807 problem details object with the ctxerr response as extension members
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	TraceID  string                 `json:"traceID,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+// NewProblem returns the problem details of a response. The instance is usually the request path
+func NewProblem(statusCode int, response ctxhttp.ErrorResponse, instance string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   response.Error.Message,
+		Instance: instance,
+		Code:     response.Error.Code,
+		TraceID:  response.Error.TraceID,
+		Fields:   response.Error.Fields,
+	}
+}
+
+var htmlTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><title>{{.StatusCode}} {{.Title}}</title></head>
+<body>
+<h1>{{.StatusCode}} {{.Title}}</h1>
+{{- with .Response.Error.Message}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Response.Error.Code}}
+<p>Code: <code>{{.}}</code></p>
+{{- end}}
+{{- with .Response.Error.TraceID}}
+<p>Trace ID: <code>{{.}}</code></p>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML returns a simple HTML error page for a response
+func HTML(statusCode int, response ctxhttp.ErrorResponse) (string, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		StatusCode int
+		Title      string
+		Response   ctxhttp.ErrorResponse
+	}{statusCode, http.StatusText(statusCode), response})
+	return buf.String(), err
+}
+
+// Text returns a response as plain text
+func Text(statusCode int, response ctxhttp.ErrorResponse) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d %s\n", statusCode, http.StatusText(statusCode))
+	if v := response.Error.Message; v != "" {
+		fmt.Fprintf(&sb, "message: %s\n", v)
+	}
+	if v := response.Error.Code; v != "" {
+		fmt.Fprintf(&sb, "code: %s\n", v)
+	}
+	if v := response.Error.TraceID; v != "" {
+		fmt.Fprintf(&sb, "traceID: %s\n", v)
+	}
+	return sb.String()
+}
+
+// writers write a response for a media type to a http.ResponseWriter
+var writers = map[string]func(w http.ResponseWriter, r *http.Request, statusCode int, response ctxhttp.ErrorResponse) error{
+	"application/json":         writeJSON,
+	MIMEApplicationProblemJSON: writeProblemJSON,
+	"text/html":                writeHTML,
+	"text/plain":               writeText,
+}
+
+// WriteResponse writes the response in the media type that best matches the Accept header of r using Negotiate:
+// JSON, RFC 7807 problem details, the HTML page from HTML or the text from Text. JSON is used when nothing matches.
+// HEAD requests get no body. It is shared by the packages that write to a http.ResponseWriter, like chi and gin.
+func WriteResponse(w http.ResponseWriter, r *http.Request, statusCode int, response ctxhttp.ErrorResponse) error {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(statusCode)
+		return nil
+	}
+	write, ok := Negotiate(r.Header.Get("Accept"), writers)
+	if !ok {
+		write = writeJSON
+	}
+	w.Header().Add("Vary", "Accept")
+	return write(w, r, statusCode, response)
+}
+
+func writeBody(w http.ResponseWriter, statusCode int, contentType string, b []byte) error {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, err := w.Write(b)
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, _ *http.Request, statusCode int, response ctxhttp.ErrorResponse) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return writeBody(w, statusCode, "application/json; charset=UTF-8", b)
+}
+
+func writeProblemJSON(w http.ResponseWriter, r *http.Request, statusCode int, response ctxhttp.ErrorResponse) error {
+	b, err := json.Marshal(NewProblem(statusCode, response, r.URL.Path))
+	if err != nil {
+		return err
+	}
+	return writeBody(w, statusCode, MIMEApplicationProblemJSON, b)
+}
+
+func writeHTML(w http.ResponseWriter, _ *http.Request, statusCode int, response ctxhttp.ErrorResponse) error {
+	html, err := HTML(statusCode, response)
+	if err != nil {
+		return err
+	}
+	return writeBody(w, statusCode, "text/html; charset=UTF-8", []byte(html))
+}
+
+func writeText(w http.ResponseWriter, _ *http.Request, statusCode int, response ctxhttp.ErrorResponse) error {
+	return writeBody(w, statusCode, "text/plain; charset=UTF-8", []byte(Text(statusCode, response)))
+}
+
+// FrameworkStatusCode returns the status code for an error that a framework also gave a status code, like a
+// routing 404. A status code set with ctxerr takes precedence over the framework code, which is ignored when 0.
+func FrameworkStatusCode(err error, statusCode, frameworkCode int) int {
+	if _, ok := ctxerr.AllFields(err)[ctxerr.FieldKeyStatusCode]; !ok && frameworkCode != 0 {
+		return frameworkCode
+	}
+	return statusCode
+}
@@ -0,0 +1,169 @@
+package nethttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxhttp "github.com/mvndaai/ctxerr/http"
+	ctxnethttp "github.com/mvndaai/ctxerrhelper/nethttp"
+)
+
+func TestNegotiate(t *testing.T) {
+	mediaTypes := map[string]string{}
+	for _, mt := range []string{"application/json", ctxnethttp.MIMEApplicationProblemJSON, "text/html", "text/plain"} {
+		mediaTypes[mt] = mt
+	}
+
+	tests := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "empty", accept: "", expected: ""},
+		{name: "any", accept: "*/*", expected: ""},
+		{name: "json", accept: "application/json", expected: "application/json"},
+		{name: "problem", accept: "application/problem+json", expected: ctxnethttp.MIMEApplicationProblemJSON},
+		{name: "html", accept: "text/html", expected: "text/html"},
+		{name: "text", accept: "text/plain", expected: "text/plain"},
+		{name: "unknown", accept: "image/png", expected: ""},
+		{name: "case", accept: "Text/HTML", expected: "text/html"},
+		{name: "browser", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expected: "text/html"},
+		{name: "quality", accept: "text/html;q=0.5, application/problem+json", expected: ctxnethttp.MIMEApplicationProblemJSON},
+		{name: "zero quality", accept: "text/html;q=0, text/plain;q=0.1", expected: "text/plain"},
+		{name: "any before match", accept: "*/*, text/html;q=0.5", expected: ""},
+		{name: "type wildcard", accept: "text/*", expected: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := ctxnethttp.Negotiate(tt.accept, mediaTypes)
+			if v != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("negotiated value did not match [%s] %v [%s]", v, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func response() ctxhttp.ErrorResponse {
+	var response ctxhttp.ErrorResponse
+	response.Error.Message = "<message>"
+	response.Error.Code = "code"
+	response.Error.TraceID = "trace"
+	return response
+}
+
+func TestNewProblem(t *testing.T) {
+	b, err := json.Marshal(ctxnethttp.NewProblem(http.StatusBadRequest, response(), "/path"))
+	if err != nil {
+		t.Fatal("problem did not marshal", err)
+	}
+	for _, s := range []string{`"type":"about:blank"`, `"title":"Bad Request"`, `"status":400`, `"detail":"\u003cmessage\u003e"`, `"instance":"/path"`, `"code":"code"`, `"traceID":"trace"`} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("problem did not contain %s\n%s", s, b)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	html, err := ctxnethttp.HTML(http.StatusBadRequest, response())
+	if err != nil {
+		t.Fatal("could not render html", err)
+	}
+	for _, s := range []string{"<h1>400 Bad Request</h1>", "<p>&lt;message&gt;</p>", "<code>code</code>", "<code>trace</code>"} {
+		if !strings.Contains(html, s) {
+			t.Errorf("html did not contain %s\n%s", s, html)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	expected := "400 Bad Request\nmessage: <message>\ncode: code\ntraceID: trace\n"
+	if v := ctxnethttp.Text(http.StatusBadRequest, response()); v != expected {
+		t.Errorf("text did not match\n%s\n%s", v, expected)
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	tests := []struct {
+		name                string
+		method              string
+		accept              string
+		expectedContentType string
+		expectedBody        []string
+	}{
+		{name: "default", accept: "", expectedContentType: "application/json", expectedBody: []string{`"code":"code"`}},
+		{name: "json", accept: "application/json", expectedContentType: "application/json", expectedBody: []string{`"code":"code"`}},
+		{
+			name:                "problem",
+			accept:              "application/problem+json",
+			expectedContentType: ctxnethttp.MIMEApplicationProblemJSON,
+			expectedBody:        []string{`"type":"about:blank"`, `"title":"Bad Request"`, `"status":400`, `"instance":"/path"`, `"code":"code"`},
+		},
+		{name: "html", accept: "text/html", expectedContentType: "text/html", expectedBody: []string{"<h1>400 Bad Request</h1>", "<code>code</code>"}},
+		{name: "text", accept: "text/plain", expectedContentType: "text/plain", expectedBody: []string{"400 Bad Request\n", "code: code\n"}},
+		{name: "head", method: http.MethodHead, accept: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			r := httptest.NewRequest(method, "/path", nil)
+			r.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			if err := ctxnethttp.WriteResponse(rec, r, http.StatusBadRequest, response()); err != nil {
+				t.Fatal("could not write response", err)
+			}
+
+			if rec.Code != http.StatusBadRequest {
+				t.Error("status code did not match", rec.Code)
+			}
+			if v := rec.Header().Get("Content-Type"); !strings.HasPrefix(v, tt.expectedContentType) {
+				t.Error("content type did not match", v, tt.expectedContentType)
+			}
+			if tt.expectedBody == nil && rec.Body.Len() != 0 {
+				t.Error("body should be empty", rec.Body.String())
+			}
+			if tt.expectedBody != nil && rec.Header().Get("Vary") != "Accept" {
+				t.Error("vary header did not match", rec.Header())
+			}
+			for _, s := range tt.expectedBody {
+				if !strings.Contains(rec.Body.String(), s) {
+					t.Errorf("body did not contain %s\n%s", s, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFrameworkStatusCode(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name          string
+		err           error
+		frameworkCode int
+		expected      int
+	}{
+		{name: "framework code", err: errors.New("route"), frameworkCode: http.StatusNotFound, expected: http.StatusNotFound},
+		{name: "no framework code", err: errors.New("route"), frameworkCode: 0, expected: http.StatusInternalServerError},
+		{name: "ctxerr status code", err: ctxerr.NewHTTP(ctx, "code", "", http.StatusConflict), frameworkCode: http.StatusNotFound, expected: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusCode, _ := ctxhttp.StatusCodeAndResponse(tt.err, false, false)
+			if v := ctxnethttp.FrameworkStatusCode(tt.err, statusCode, tt.frameworkCode); v != tt.expected {
+				t.Error("status code did not match", v, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package opencensus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"github.com/mvndaai/ctxerrhelper/opencensus"
+	"go.opencensus.io/trace"
+)
+
+func TestTraceFields(t *testing.T) {
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(opencensus.TraceFields{URLTemplate: "https://tracing.example.com/{trace_id}?span={span_id}"}.CreateHook)
+
+	ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+	defer span.End()
+	sc := span.SpanContext()
+
+	fields := ctxerr.AllFields(in.New(ctx, "code"))
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyTraceID:      sc.TraceID.String(),
+		ctxerrfields.FieldKeySpanID:       sc.SpanID.String(),
+		ctxerrfields.FieldKeyTraceSampled: true,
+		ctxerrfields.FieldKeyTraceURL:     "https://tracing.example.com/" + sc.TraceID.String() + "?span=" + sc.SpanID.String(),
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestTraceFieldsNoSpan(t *testing.T) {
+	fields := ctxerr.Fields(opencensus.CreateHook(context.Background(), "code", nil))
+	if _, ok := fields[ctxerrfields.FieldKeyTraceID]; ok {
+		t.Error("fields should not be added without a span", fields)
+	}
+}
@@ -3,8 +3,14 @@ module github.com/mvndaai/ctxerrhelper/opencensus
 go 1.20
 
 require (
-	github.com/mvndaai/ctxerr v0.12.0
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
 	go.opencensus.io v0.24.0
 )
 
 require github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+)
@@ -0,0 +1,80 @@
+package opencensus
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"go.opencensus.io/trace"
+)
+
+type contexter interface {
+	Context() context.Context
+}
+
+// SpanConfig configures how handled errors are recorded on the span in the error's context
+type SpanConfig struct {
+	// IsWarning tells if an error is a warning, which is recorded without an error status. Defaults to ctxerrfields.IsWarning
+	IsWarning func(error) bool
+	// Fields function to get fields to add as attributes. Defaults to ctxerr.AllFields
+	Fields func(error) map[string]any
+}
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook using the default SpanConfig
+func HandleHook(err error) { SpanConfig{}.HandleHook(err) }
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook.
+// The fields are added to the span as attributes, the error is added as an annotation and
+// the span status is set to an error unless the error is a warning.
+func (c SpanConfig) HandleHook(err error) {
+	if err == nil {
+		return
+	}
+	v, ok := err.(contexter)
+	if !ok {
+		return
+	}
+	span := trace.FromContext(v.Context())
+	if span == nil || !span.IsRecordingEvents() {
+		return
+	}
+
+	ff := c.Fields
+	if ff == nil {
+		ff = ctxerr.AllFields
+	}
+	attrs := Attributes(ff(err))
+	span.AddAttributes(attrs...)
+	span.Annotate(attrs, err.Error())
+
+	isWarning := c.IsWarning
+	if isWarning == nil {
+		isWarning = ctxerrfields.IsWarning
+	}
+	if !isWarning(err) {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+}
+
+// Attributes converts fields to opencensus attributes sorted by key.
+// Values are converted with ctxerrfields.Scalar so values without a matching attribute type are added as strings.
+func Attributes(fields map[string]any) []trace.Attribute {
+	keys := ctxerrfields.SortedKeys(fields)
+	attrs := make([]trace.Attribute, len(keys))
+	for i, k := range keys {
+		attrs[i] = attribute(k, fields[k])
+	}
+	return attrs
+}
+
+func attribute(k string, v any) trace.Attribute {
+	switch v := ctxerrfields.Scalar(v).(type) {
+	case bool:
+		return trace.BoolAttribute(k, v)
+	case int64:
+		return trace.Int64Attribute(k, v)
+	case float64:
+		return trace.Float64Attribute(k, v)
+	}
+	return trace.StringAttribute(k, ctxerrfields.String(v))
+}
@@ -0,0 +1,106 @@
+package opencensus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	"github.com/mvndaai/ctxerrhelper/opencensus"
+	"go.opencensus.io/trace"
+)
+
+type exporter struct {
+	spans []*trace.SpanData
+}
+
+func (e *exporter) ExportSpan(s *trace.SpanData) { e.spans = append(e.spans, s) }
+
+func record(t *testing.T, c opencensus.SpanConfig, toErr func(context.Context) error) *trace.SpanData {
+	t.Helper()
+	e := &exporter{}
+	trace.RegisterExporter(e)
+	defer trace.UnregisterExporter(e)
+
+	ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+	c.HandleHook(toErr(ctx))
+	span.End()
+
+	if len(e.spans) != 1 {
+		t.Fatal("expected one span", len(e.spans))
+	}
+	return e.spans[0]
+}
+
+func TestHandleHook(t *testing.T) {
+	tests := []struct {
+		name          string
+		toErr         func(context.Context) error
+		expectedError bool
+	}{
+		{
+			name: "no status code",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.New(ctx, "code", "message")
+			},
+			expectedError: true,
+		},
+		{
+			name: "server error",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusBadGateway, "message")
+			},
+			expectedError: true,
+		},
+		{
+			name: "warning",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound, "message")
+			},
+			expectedError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := record(t, opencensus.SpanConfig{}, func(ctx context.Context) error {
+				ctx = ctxerr.SetFields(ctx, map[string]interface{}{
+					"str": "a", "int": 1, "uint": uint(2), "uint64": uint64(3), "bool": true, "float": 1.5, "map": map[string]int{"a": 1},
+				})
+				return tt.toErr(ctx)
+			})
+
+			expected := map[string]interface{}{
+				"str": "a", "int": int64(1), "uint": int64(2), "uint64": int64(3), "bool": true, "float": 1.5, "map": `{"a":1}`,
+			}
+			for k, v := range expected {
+				if s.Attributes[k] != v {
+					t.Errorf("attribute %s did not match [%#v] [%#v]", k, s.Attributes[k], v)
+				}
+			}
+			if len(s.Annotations) != 1 || s.Annotations[0].Message != "message" {
+				t.Error("error should be annotated", s.Annotations)
+			}
+			if isError := s.Status.Code != trace.StatusCodeOK; isError != tt.expectedError {
+				t.Error("status did not match", s.Status)
+			}
+		})
+	}
+}
+
+func TestHandleHookIsWarning(t *testing.T) {
+	c := opencensus.SpanConfig{IsWarning: func(error) bool { return true }}
+	s := record(t, c, func(ctx context.Context) error {
+		return ctxerr.New(ctx, "code", "message")
+	})
+	if s.Status.Code != trace.StatusCodeOK {
+		t.Error("warnings should not set the status", s.Status)
+	}
+}
+
+func TestHandleHookNoSpan(t *testing.T) {
+	opencensus.HandleHook(errors.New("no context"))
+	opencensus.HandleHook(ctxerr.New(context.Background(), "code"))
+	opencensus.HandleHook(nil)
+}
@@ -1,9 +1,21 @@
 /*
-Package opencensus can be imported to use opencensus for tracing
+Package opencensus uses opencensus for tracing
 
-# As a side effect of importing the package the http.TraceID function gets replaced
+Call Install to make http.TraceID use opencensus. Importing the package no longer replaces http.TraceID as a side effect.
+Use the traceid package to combine TraceID with other trace ID sources.
 
-import _ "github.com/mvndaai/ctxerr/http/trace/opencensus"
+	import "github.com/mvndaai/ctxerrhelper/opencensus"
+
+	func main() {
+		opencensus.Install()
+		...
+	}
+
+Add HandleHook to record handled errors on the span in the error's context and
+a TraceFields create hook to copy the trace and span IDs into the error fields
+
+	ctxerr.AddHandleHook(opencensus.HandleHook)
+	ctxerr.AddCreateHook(opencensus.TraceFields{URLTemplate: "https://tracing.example.com/trace/{trace_id}"}.CreateHook)
 */
 package opencensus
 
@@ -14,8 +26,11 @@ import (
 	"go.opencensus.io/trace"
 )
 
-func init() {
+// Install replaces http.TraceID with TraceID and returns a function that restores the previous value
+func Install() (restore func()) {
+	previous := http.TraceID
 	http.TraceID = TraceID
+	return func() { http.TraceID = previous }
 }
 
 // TraceID uses opencensus to get the trace ID from the context
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/mvndaai/ctxerr/http"
+	"github.com/mvndaai/ctxerrhelper/opencensus"
 	"go.opencensus.io/trace"
 )
 
@@ -23,8 +24,18 @@ func TestTraceID(t *testing.T) {
 	parent := trace.SpanContext{TraceID: tid}
 	ctx, _ = trace.StartSpanWithRemoteParent(ctx, "", parent)
 
+	if out := http.TraceID(ctx); out == traceID {
+		t.Fatal("http.TraceID should not use opencensus before Install")
+	}
+
+	restore := opencensus.Install()
 	out := http.TraceID(ctx)
 	if out != traceID {
 		t.Error("Trace ID did not match", out, traceID)
 	}
+
+	restore()
+	if out := http.TraceID(ctx); out == traceID {
+		t.Error("http.TraceID should be restored")
+	}
 }
@@ -0,0 +1,32 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFields configures a create hook that copies the trace ID, span ID and sampled flag into the error fields
+type TraceFields struct {
+	// URLTemplate adds a link to a trace viewer as a field, like "https://tracing.example.com/trace/{trace_id}".
+	// See ctxerrfields.TraceFields for the placeholders. No link is added when empty
+	URLTemplate string
+}
+
+// CreateHook is a hook that can be added to ctxerr.AddCreateHook using TraceFields without a URL template
+func CreateHook(ctx context.Context, code string, wrapping error) context.Context {
+	return TraceFields{}.CreateHook(ctx, code, wrapping)
+}
+
+// CreateHook is a hook that can be added to ctxerr.AddCreateHook.
+// Nothing is added if there is no valid span context in the context.
+func (tf TraceFields) CreateHook(ctx context.Context, _ string, _ error) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	return ctxerr.SetFields(ctx, ctxerrfields.TraceFields(sc.TraceID().String(), sc.SpanID().String(), sc.IsSampled(), tf.URLTemplate))
+}
@@ -0,0 +1,51 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceFields(t *testing.T) {
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxotel.TraceFields{URLTemplate: "https://tracing.example.com/{trace_id}?span={span_id}"}.CreateHook)
+
+	ctx := spanContext(t)
+	fields := ctxerr.AllFields(in.New(ctx, "code"))
+
+	expected := map[string]interface{}{
+		ctxerrfields.FieldKeyTraceID:      traceID,
+		ctxerrfields.FieldKeySpanID:       spanID,
+		ctxerrfields.FieldKeyTraceSampled: false,
+		ctxerrfields.FieldKeyTraceURL:     "https://tracing.example.com/" + traceID + "?span=" + spanID,
+	}
+	for k, v := range expected {
+		if fields[k] != v {
+			t.Errorf("field %s did not match [%v] [%v]", k, fields[k], v)
+		}
+	}
+}
+
+func TestTraceFieldsSampled(t *testing.T) {
+	sc := trace.SpanContextFromContext(spanContext(t)).WithTraceFlags(trace.FlagsSampled)
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	fields := ctxerr.Fields(ctxotel.CreateHook(ctx, "code", nil))
+	if fields[ctxerrfields.FieldKeyTraceSampled] != true {
+		t.Error("sampled did not match", fields)
+	}
+	if _, ok := fields[ctxerrfields.FieldKeyTraceURL]; ok {
+		t.Error("url should not be added without a template", fields)
+	}
+}
+
+func TestTraceFieldsNoSpan(t *testing.T) {
+	fields := ctxerr.Fields(ctxotel.CreateHook(context.Background(), "code", nil))
+	if _, ok := fields[ctxerrfields.FieldKeyTraceID]; ok {
+		t.Error("fields should not be added without a span", fields)
+	}
+}
@@ -0,0 +1,94 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type contexter interface {
+	Context() context.Context
+}
+
+// SpanConfig configures how handled errors are recorded on the span in the error's context
+type SpanConfig struct {
+	// IsWarning tells if an error is a warning, which is recorded without an error status. Defaults to ctxerrfields.IsWarning
+	IsWarning func(error) bool
+	// Fields function to get fields to add as attributes. Defaults to ctxerr.AllFields
+	Fields func(error) map[string]any
+}
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook using the default SpanConfig
+func HandleHook(err error) { SpanConfig{}.HandleHook(err) }
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook.
+// The fields are added to the span as attributes, the error is recorded as an exception event and
+// the span status is set to Error unless the error is a warning.
+func (c SpanConfig) HandleHook(err error) {
+	if err == nil {
+		return
+	}
+	v, ok := err.(contexter)
+	if !ok {
+		return
+	}
+	span := trace.SpanFromContext(v.Context())
+	if !span.IsRecording() {
+		return
+	}
+
+	ff := c.Fields
+	if ff == nil {
+		ff = ctxerr.AllFields
+	}
+	span.SetAttributes(Attributes(ff(err))...)
+	span.RecordError(err)
+
+	isWarning := c.IsWarning
+	if isWarning == nil {
+		isWarning = ctxerrfields.IsWarning
+	}
+	if !isWarning(err) {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Attributes converts fields to OpenTelemetry attributes sorted by key.
+// Values are converted with ctxerrfields.Scalar so values without a matching attribute type are added as strings.
+func Attributes(fields map[string]any) []attribute.KeyValue {
+	keys := ctxerrfields.SortedKeys(fields)
+	attrs := make([]attribute.KeyValue, len(keys))
+	for i, k := range keys {
+		attrs[i] = keyValue(k, fields[k])
+	}
+	return attrs
+}
+
+func keyValue(k string, v any) attribute.KeyValue {
+	switch v := v.(type) {
+	case []string:
+		return attribute.StringSlice(k, v)
+	case []bool:
+		return attribute.BoolSlice(k, v)
+	case []int:
+		return attribute.IntSlice(k, v)
+	case []int64:
+		return attribute.Int64Slice(k, v)
+	case []float64:
+		return attribute.Float64Slice(k, v)
+	}
+
+	switch v := ctxerrfields.Scalar(v).(type) {
+	case bool:
+		return attribute.Bool(k, v)
+	case int64:
+		return attribute.Int64(k, v)
+	case float64:
+		return attribute.Float64(k, v)
+	}
+	return attribute.String(k, ctxerrfields.String(v))
+}
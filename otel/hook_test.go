@@ -0,0 +1,116 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func record(t *testing.T, c ctxotel.SpanConfig, toErr func(context.Context) error) sdktrace.ReadOnlySpan {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+	c.HandleHook(toErr(ctx))
+	span.End()
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatal("expected one span", len(spans))
+	}
+	return spans[0]
+}
+
+func TestHandleHook(t *testing.T) {
+	tests := []struct {
+		name           string
+		toErr          func(context.Context) error
+		expectedStatus codes.Code
+	}{
+		{
+			name: "no status code",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.New(ctx, "code", "message")
+			},
+			expectedStatus: codes.Error,
+		},
+		{
+			name: "server error",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusBadGateway, "message")
+			},
+			expectedStatus: codes.Error,
+		},
+		{
+			name: "warning",
+			toErr: func(ctx context.Context) error {
+				return ctxerr.NewHTTP(ctx, "code", "", http.StatusNotFound, "message")
+			},
+			expectedStatus: codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := record(t, ctxotel.SpanConfig{}, func(ctx context.Context) error {
+				ctx = ctxerr.SetFields(ctx, map[string]interface{}{
+					"str": "a", "int": 1, "uint": uint(2), "uint64": uint64(3), "bool": true, "float": 1.5,
+					"slice": []string{"a"}, "map": map[string]int{"a": 1},
+				})
+				return tt.toErr(ctx)
+			})
+
+			attrs := map[attribute.Key]attribute.Value{}
+			for _, kv := range s.Attributes() {
+				attrs[kv.Key] = kv.Value
+			}
+			expected := map[attribute.Key]attribute.Value{
+				"str":    attribute.StringValue("a"),
+				"int":    attribute.IntValue(1),
+				"uint":   attribute.Int64Value(2),
+				"uint64": attribute.Int64Value(3),
+				"bool":   attribute.BoolValue(true),
+				"float":  attribute.Float64Value(1.5),
+				"slice":  attribute.StringSliceValue([]string{"a"}),
+				"map":    attribute.StringValue(`{"a":1}`),
+			}
+			for k, v := range expected {
+				if attrs[k] != v {
+					t.Errorf("attribute %s did not match [%#v] [%#v]", k, attrs[k].Emit(), v.Emit())
+				}
+			}
+
+			if events := s.Events(); len(events) != 1 || events[0].Name != "exception" {
+				t.Error("error should be recorded", events)
+			}
+			if s.Status().Code != tt.expectedStatus {
+				t.Error("status did not match", s.Status())
+			}
+		})
+	}
+}
+
+func TestHandleHookIsWarning(t *testing.T) {
+	c := ctxotel.SpanConfig{IsWarning: func(error) bool { return true }}
+	s := record(t, c, func(ctx context.Context) error {
+		return ctxerr.New(ctx, "code", "message")
+	})
+	if s.Status().Code != codes.Unset {
+		t.Error("warnings should not set the status", s.Status())
+	}
+}
+
+func TestHandleHookNoSpan(t *testing.T) {
+	ctxotel.HandleHook(errors.New("no context"))
+	ctxotel.HandleHook(ctxerr.New(context.Background(), "code"))
+	ctxotel.HandleHook(nil)
+}
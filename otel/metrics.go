@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultCounterName is the name of the counter when MetricConfig.Name is empty
+const DefaultCounterName = "ctxerr.handled_errors"
+
+// DefaultMetricSelection is used when MetricConfig.Selection is empty.
+// Only the code and status code are used because every attribute value adds a time series.
+var DefaultMetricSelection = ctxerrfields.Selection{Include: []string{ctxerr.FieldKeyCode, ctxerr.FieldKeyStatusCode}}
+
+// MetricConfig configures an ErrorCounter
+type MetricConfig struct {
+	// Name is the counter name. Defaults to DefaultCounterName
+	Name string
+	// Description is the counter description
+	Description string
+	// Selection chooses which fields are added as attributes. Defaults to DefaultMetricSelection
+	Selection ctxerrfields.Selection
+}
+
+// ErrorCounter counts handled errors with an Int64Counter
+type ErrorCounter struct {
+	counter   metric.Int64Counter
+	selection ctxerrfields.Selection
+}
+
+// NewErrorCounter creates the counter with the meter
+func NewErrorCounter(meter metric.Meter, c MetricConfig) (*ErrorCounter, error) {
+	name := c.Name
+	if name == "" {
+		name = DefaultCounterName
+	}
+	description := c.Description
+	if description == "" {
+		description = "Number of errors handled with ctxerr.Handle"
+	}
+	selection := c.Selection
+	if selection.IsZero() {
+		selection = DefaultMetricSelection
+	}
+
+	counter, err := meter.Int64Counter(name, metric.WithDescription(description), metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorCounter{counter: counter, selection: selection}, nil
+}
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook to count handled errors.
+// The context of the error is used so exemplars can link to its span.
+func (ec *ErrorCounter) HandleHook(err error) {
+	if err == nil {
+		return
+	}
+	ctx := context.Background()
+	if v, ok := err.(contexter); ok {
+		ctx = v.Context()
+	}
+	attrs := Attributes(ec.selection.Select(ctxerr.AllFields(err)))
+	ec.counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,98 @@
+package otel_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) []metricdata.DataPoint[int64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal("could not collect", err)
+	}
+	if len(rm.ScopeMetrics) != 1 || len(rm.ScopeMetrics[0].Metrics) != 1 {
+		t.Fatal("expected one metric", rm.ScopeMetrics)
+	}
+	sum, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatal("metric was not an int64 sum", rm.ScopeMetrics[0].Metrics[0].Data)
+	}
+	return sum.DataPoints
+}
+
+func newCounter(t *testing.T, c ctxotel.MetricConfig) (*ctxotel.ErrorCounter, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	ec, err := ctxotel.NewErrorCounter(mp.Meter("test"), c)
+	if err != nil {
+		t.Fatal("could not create counter", err)
+	}
+	return ec, reader
+}
+
+func TestErrorCounter(t *testing.T) {
+	ec, reader := newCounter(t, ctxotel.MetricConfig{})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(ec.HandleHook)
+
+	ctx := ctxerr.SetField(context.Background(), "user", "a")
+	in.Handle(in.NewHTTP(ctx, "not_found", "", http.StatusNotFound))
+	in.Handle(in.NewHTTP(ctx, "not_found", "", http.StatusNotFound))
+	in.Handle(in.New(ctx, "other"))
+	in.Handle(nil)
+
+	points := collect(t, reader)
+	if len(points) != 2 {
+		t.Fatal("expected two data points", points)
+	}
+	for _, p := range points {
+		if _, ok := p.Attributes.Value("user"); ok {
+			t.Error("fields outside of the selection should not be attributes", p.Attributes)
+		}
+		code, _ := p.Attributes.Value(ctxerr.FieldKeyCode)
+		switch code.AsString() {
+		case "not_found":
+			if p.Value != 2 {
+				t.Error("count did not match", p.Value)
+			}
+			if v, _ := p.Attributes.Value(ctxerr.FieldKeyStatusCode); v != attribute.IntValue(http.StatusNotFound) {
+				t.Error("status code attribute did not match", v.Emit())
+			}
+		case "other":
+			if p.Value != 1 {
+				t.Error("count did not match", p.Value)
+			}
+		default:
+			t.Error("unexpected code", code.Emit())
+		}
+	}
+}
+
+func TestErrorCounterSelection(t *testing.T) {
+	ec, reader := newCounter(t, ctxotel.MetricConfig{Selection: ctxerrfields.Selection{Include: []string{"tenant"}}})
+
+	ec.HandleHook(ctxerr.New(ctxerr.SetField(context.Background(), "tenant", "a"), "code"))
+
+	points := collect(t, reader)
+	if len(points) != 1 {
+		t.Fatal("expected one data point", points)
+	}
+	if v, _ := points[0].Attributes.Value("tenant"); v.AsString() != "a" {
+		t.Error("tenant attribute did not match", v.Emit())
+	}
+	if points[0].Attributes.Len() != 1 {
+		t.Error("only the selected fields should be attributes", points[0].Attributes)
+	}
+}
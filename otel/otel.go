@@ -0,0 +1,52 @@
+/*
+Package otel uses OpenTelemetry (https://opentelemetry.io) for tracing and metrics.
+
+Call Install to make http.TraceID use OpenTelemetry. Use the traceid package to combine TraceID with other trace ID sources.
+Add HandleHook to record handled errors on the span in the error's context and
+a TraceFields create hook to copy the trace and span IDs into the error fields.
+An ErrorCounter counts handled errors with attributes chosen from the error fields.
+
+	import ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+
+	func main() {
+		ctxotel.Install()
+		ctxerr.AddHandleHook(ctxotel.HandleHook)
+		ctxerr.AddCreateHook(ctxotel.TraceFields{URLTemplate: "https://tracing.example.com/trace/{trace_id}"}.CreateHook)
+
+		counter, err := ctxotel.NewErrorCounter(otel.Meter("app"), ctxotel.MetricConfig{})
+		...
+		ctxerr.AddHandleHook(counter.HandleHook)
+		...
+	}
+*/
+package otel
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr/http"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Install replaces http.TraceID with TraceID and returns a function that restores the previous value
+func Install() (restore func()) {
+	previous := http.TraceID
+	http.TraceID = TraceID
+	return func() { http.TraceID = previous }
+}
+
+// TraceID uses OpenTelemetry to get the trace ID from the span context in the context
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// SpanID uses OpenTelemetry to get the span ID from the span context in the context
+func SpanID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() {
+		return sc.SpanID().String()
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvndaai/ctxerr/http"
+	ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceID = "12e3249570b71c725235bbec6d4018fa"
+	spanID  = "235bbec6d4018fa1"
+)
+
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		t.Fatalf("could not convert traceID (%s) to hex", traceID)
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		t.Fatalf("could not convert spanID (%s) to hex", spanID)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: sid})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestTraceID(t *testing.T) {
+	ctx := spanContext(t)
+	if out := ctxotel.TraceID(ctx); out != traceID {
+		t.Error("Trace ID did not match", out, traceID)
+	}
+	if out := ctxotel.TraceID(context.Background()); out != "" {
+		t.Error("Trace ID should be empty without a span", out)
+	}
+}
+
+func TestSpanID(t *testing.T) {
+	ctx := spanContext(t)
+	if out := ctxotel.SpanID(ctx); out != spanID {
+		t.Error("Span ID did not match", out, spanID)
+	}
+	if out := ctxotel.SpanID(context.Background()); out != "" {
+		t.Error("Span ID should be empty without a span", out)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	ctx := spanContext(t)
+	if out := http.TraceID(ctx); out == traceID {
+		t.Fatal("http.TraceID should not use OpenTelemetry before Install")
+	}
+
+	restore := ctxotel.Install()
+	if out := http.TraceID(ctx); out != traceID {
+		t.Error("Trace ID did not match", out, traceID)
+	}
+
+	restore()
+	if out := http.TraceID(ctx); out == traceID {
+		t.Error("http.TraceID should be restored")
+	}
+}
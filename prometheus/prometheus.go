@@ -0,0 +1,206 @@
+/*
+Package prometheus counts handled ctxerr errors with Prometheus (https://prometheus.io).
+
+	import ctxprometheus "github.com/mvndaai/ctxerrhelper/prometheus"
+
+	func main() {
+		c := ctxprometheus.NewCollector(ctxprometheus.Config{
+			Fields:        []string{ctxerrfields.FieldKeyHTTPRoute},
+			AllowedValues: map[string][]string{ctxprometheus.LabelCode: {"not_found", "panic"}},
+		})
+		prometheus.MustRegister(c)
+		ctxerr.AddHandleHook(c.HandleHook)
+		...
+	}
+*/
+package prometheus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultName is the name of the counter when Config.Name is empty
+	DefaultName = "ctxerr_handled_errors_total"
+	// LabelCode is the label for the ctxerr code
+	LabelCode = "code"
+	// LabelStatusClass is the label for the class of the status code, like 4xx. Errors without a status code are 5xx
+	LabelStatusClass = "status_class"
+	// OverflowValue replaces label values that are not allowed
+	OverflowValue = "other"
+	// DefaultMaxValues is the limit of values per label when Config.MaxValues is zero
+	DefaultMaxValues = 100
+)
+
+// Config configures the counter of a Collector
+type Config struct {
+	// Namespace, Subsystem and Name are combined into the counter name. Name defaults to DefaultName
+	Namespace, Subsystem, Name string
+	// Help is the counter help text
+	Help string
+	// Fields are ctxerr field keys that are added as labels. They must be valid Prometheus label names.
+	// LabelCode, LabelStatusClass and repeated keys are ignored because they are already labels
+	Fields []string
+	// AllowedValues are the values allowed for a label, including LabelCode. Any other value is counted as OverflowValue
+	AllowedValues map[string][]string
+	// MaxValues limits the number of values of labels without AllowedValues. Values after the limit are counted as OverflowValue.
+	// Zero uses DefaultMaxValues and a negative value means no limit
+	MaxValues int
+	// LogError is a way to log an error not using ctxerr.Handle to avoid circular errors
+	LogError func(error)
+}
+
+// Collector is a prometheus.Collector with a counter vector of handled errors
+type Collector struct {
+	counter   *prometheus.CounterVec
+	fields    []string
+	allowed   map[string]map[string]bool
+	maxValues int
+	logError  func(error)
+
+	mu   sync.Mutex
+	seen map[string]map[string]bool
+}
+
+// NewCollector creates a Collector. It panics if a field is not a valid label name, like prometheus.NewCounterVec
+func NewCollector(c Config) *Collector {
+	name := c.Name
+	if name == "" {
+		name = DefaultName
+	}
+	help := c.Help
+	if help == "" {
+		help = "Number of errors handled with ctxerr.Handle"
+	}
+
+	allowed := map[string]map[string]bool{}
+	for label, values := range c.AllowedValues {
+		allowed[label] = map[string]bool{}
+		for _, v := range values {
+			allowed[label][v] = true
+		}
+	}
+
+	maxValues := c.MaxValues
+	if maxValues == 0 {
+		maxValues = DefaultMaxValues
+	}
+
+	labels := []string{LabelCode, LabelStatusClass}
+	var fields []string
+	for _, k := range c.Fields {
+		if !contains(labels, k) {
+			labels = append(labels, k)
+			fields = append(fields, k)
+		}
+	}
+	return &Collector{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.Namespace,
+			Subsystem: c.Subsystem,
+			Name:      name,
+			Help:      help,
+		}, labels),
+		fields:    fields,
+		allowed:   allowed,
+		maxValues: maxValues,
+		logError:  c.LogError,
+		seen:      map[string]map[string]bool{},
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) { c.counter.Describe(ch) }
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) { c.counter.Collect(ch) }
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook to count handled errors
+func (c *Collector) HandleHook(err error) {
+	if err == nil {
+		return
+	}
+	fields := ctxerr.AllFields(err)
+
+	labels := prometheus.Labels{
+		LabelCode:        c.value(LabelCode, labelValue(fields[ctxerr.FieldKeyCode])),
+		LabelStatusClass: c.value(LabelStatusClass, StatusClass(fields[ctxerr.FieldKeyStatusCode])),
+	}
+	for _, k := range c.fields {
+		labels[k] = c.value(k, labelValue(fields[k]))
+	}
+	counter, err := c.counter.GetMetricWith(labels)
+	if err != nil {
+		if c.logError != nil {
+			c.logError(err)
+		}
+		return
+	}
+	counter.Inc()
+}
+
+// value returns the label value or OverflowValue if it is not allowed
+func (c *Collector) value(label, v string) string {
+	if allowed, ok := c.allowed[label]; ok {
+		if allowed[v] {
+			return v
+		}
+		return OverflowValue
+	}
+	if c.maxValues <= 0 {
+		return v
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seen, ok := c.seen[label]
+	if !ok {
+		seen = map[string]bool{}
+		c.seen[label] = seen
+	}
+	if seen[v] {
+		return v
+	}
+	if len(seen) >= c.maxValues {
+		return OverflowValue
+	}
+	seen[v] = true
+	return v
+}
+
+// StatusClass returns the class of a status code field value, like 4xx. Missing or invalid status codes are 5xx
+// because ctxerr/http responds with a 500 for them
+func StatusClass(statusCode interface{}) string {
+	sc, _ := ctxerrfields.StatusCode(statusCode)
+	if sc < 100 || sc > 599 {
+		sc = 500
+	}
+	return fmt.Sprintf("%dxx", sc/100)
+}
+
+// labelValue returns a field value as a string, replacing invalid UTF-8 that Prometheus rejects
+func labelValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(ctxerrfields.Value(v))
+	}
+	return strings.ToValidUTF8(s, "\uFFFD")
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
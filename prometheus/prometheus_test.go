@@ -0,0 +1,201 @@
+package prometheus_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxprometheus "github.com/mvndaai/ctxerrhelper/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHandleHook(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{Fields: []string{ctxerrfields.FieldKeyHTTPRoute}})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	ctx := ctxerr.SetField(context.Background(), ctxerrfields.FieldKeyHTTPRoute, "/users/:id")
+	in.Handle(in.NewHTTP(ctx, "not_found", "", http.StatusNotFound, "message"))
+	in.Handle(in.NewHTTP(ctx, "not_found", "", http.StatusNotFound, "message"))
+	in.Handle(in.New(context.Background(), "unknown", "message"))
+	in.Handle(nil)
+
+	expected := `
+# HELP ctxerr_handled_errors_total Number of errors handled with ctxerr.Handle
+# TYPE ctxerr_handled_errors_total counter
+ctxerr_handled_errors_total{code="not_found",http_route="/users/:id",status_class="4xx"} 2
+ctxerr_handled_errors_total{code="unknown",http_route="",status_class="5xx"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAllowedValues(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{
+		Name:          "errors_total",
+		AllowedValues: map[string][]string{ctxprometheus.LabelCode: {"allowed"}},
+	})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	for _, code := range []string{"allowed", "a", "b"} {
+		in.Handle(in.New(context.Background(), code))
+	}
+
+	expected := `
+# HELP errors_total Number of errors handled with ctxerr.Handle
+# TYPE errors_total counter
+errors_total{code="allowed",status_class="5xx"} 1
+errors_total{code="other",status_class="5xx"} 2
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAllowedStatusClass(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{
+		Name:          "errors_total",
+		AllowedValues: map[string][]string{ctxprometheus.LabelStatusClass: {"4xx"}},
+	})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	in.Handle(in.NewHTTP(context.Background(), "code", "", http.StatusNotFound))
+	in.Handle(in.NewHTTP(context.Background(), "code", "", http.StatusBadGateway))
+
+	expected := `
+# HELP errors_total Number of errors handled with ctxerr.Handle
+# TYPE errors_total counter
+errors_total{code="code",status_class="4xx"} 1
+errors_total{code="code",status_class="other"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReservedFields(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{
+		Name:   "errors_total",
+		Fields: []string{ctxprometheus.LabelCode, ctxprometheus.LabelStatusClass, "route", "route"},
+	})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"route": "/", ctxprometheus.LabelCode: "field"})
+	in.Handle(in.New(ctx, "code"))
+
+	expected := `
+# HELP errors_total Number of errors handled with ctxerr.Handle
+# TYPE errors_total counter
+errors_total{code="code",route="/",status_class="5xx"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaxValues(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{MaxValues: 2})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	for _, code := range []string{"a", "b", "c", "d", "a"} {
+		in.Handle(in.New(context.Background(), code))
+	}
+
+	if n := testutil.CollectAndCount(c); n != 3 {
+		t.Error("series count did not match", n)
+	}
+}
+
+func TestDefaultMaxValues(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	for i := 0; i < ctxprometheus.DefaultMaxValues+10; i++ {
+		in.Handle(in.New(context.Background(), strconv.Itoa(i)))
+	}
+
+	if n := testutil.CollectAndCount(c); n != ctxprometheus.DefaultMaxValues+1 {
+		t.Error("series count did not match", n)
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{
+		Name:     "errors_total",
+		Fields:   []string{"route"},
+		LogError: func(err error) { t.Error("unexpected error", err) },
+	})
+
+	in := ctxerr.NewInstance()
+	in.AddCreateHook(ctxerr.SetCodeHook)
+	in.AddHandleHook(c.HandleHook)
+
+	ctx := ctxerr.SetField(context.Background(), "route", "/\xff")
+	in.Handle(in.New(ctx, "code\xfe"))
+
+	expected := `
+# HELP errors_total Number of errors handled with ctxerr.Handle
+# TYPE errors_total counter
+errors_total{code="code�",route="/�",status_class="5xx"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c := ctxprometheus.NewCollector(ctxprometheus.Config{Namespace: "app", Fields: []string{"tenant"}})
+	if err := reg.Register(c); err != nil {
+		t.Fatal("could not register", err)
+	}
+
+	c.HandleHook(ctxerr.New(ctxerr.SetField(context.Background(), "tenant", 1), "code"))
+
+	if n, err := testutil.GatherAndCount(reg, "app_ctxerr_handled_errors_total"); err != nil || n != 1 {
+		t.Error("series count did not match", n, err)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		in       interface{}
+		expected string
+	}{
+		{in: 404, expected: "4xx"},
+		{in: "503", expected: "5xx"},
+		{in: 200, expected: "2xx"},
+		{in: int64(429), expected: "4xx"},
+		{in: float64(302), expected: "3xx"},
+		{in: nil, expected: "5xx"},
+		{in: 42, expected: "5xx"},
+	}
+	for _, tt := range tests {
+		if out := ctxprometheus.StatusClass(tt.in); out != tt.expected {
+			t.Error("class did not match", tt.in, out, tt.expected)
+		}
+	}
+}
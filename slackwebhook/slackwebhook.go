@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
 )
 
 type (
@@ -62,6 +63,8 @@ type Config struct {
 	NotPretty bool
 	// Fields function to get feilds to log. Defaults to ctxerr.AllFields
 	Fields func(error) map[string]any
+	// Selection chooses which of the fields are sent. Defaults to all fields
+	Selection ctxerrfields.Selection
 	// ContextHooks hooks for adding to the message from a context in the error
 	ContextHooks []ContextHook
 	// Print Icon and username in message because new apps don't allow changing it
@@ -111,7 +114,7 @@ func (c Config) ToMessage(err error) *Message {
 	if ff == nil {
 		ff = ctxerr.AllFields
 	}
-	fields := ff(err)
+	fields := ctxerrfields.Sanitize(c.Selection.Select(ff(err)))
 	if len(fields) > 0 {
 		a := MessageAttachment{Color: c.ColorError}
 		if c.IsWarning != nil && c.IsWarning(err) {
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
 	"github.com/mvndaai/ctxerrhelper/slackwebhook"
 	"github.com/stretchr/testify/assert"
 )
@@ -111,6 +112,20 @@ func TestToMessage(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name:   "selection",
+			config: slackwebhook.Config{NotPretty: true, Selection: ctxerrfields.Selection{Exclude: []string{"secret"}}},
+			err: func() error {
+				ctx := ctxerr.SetField(context.Background(), "secret", "s")
+				return ctxerrIn.New(ctx, "code", "msg")
+			}(),
+			expected: &slackwebhook.Message{
+				Text: "msg",
+				Attachments: []slackwebhook.MessageAttachment{{
+					Text: "```{\"error_code\":\"code\"}```",
+				}},
+			},
+		},
 		{
 			name:   "non json",
 			config: slackwebhook.Config{},
@@ -122,7 +137,7 @@ func TestToMessage(t *testing.T) {
 			expected: &slackwebhook.Message{
 				Text: "msg",
 				Attachments: []slackwebhook.MessageAttachment{{
-					Text: fmt.Sprintf("```%s```", map[string]any{"error_code": "code", "func": testFunc}),
+					Text: "```{\n\"error_code\": \"code\",\n\"func\": \"func()\"\n}```",
 				}},
 			},
 		},
@@ -0,0 +1,13 @@
+module github.com/mvndaai/ctxerrhelper/slog
+
+go 1.22
+
+require (
+	github.com/mvndaai/ctxerr v0.13.0
+	github.com/mvndaai/ctxerrhelper/fields v0.1.0
+)
+
+// The shared modules are used from this repository until they are tagged
+replace (
+	github.com/mvndaai/ctxerrhelper/fields => ../fields
+)
@@ -0,0 +1,188 @@
+/*
+Package ctxerrslog has helpers to use ctxerr with log/slog (https://pkg.go.dev/log/slog).
+
+	import ctxerrslog "github.com/mvndaai/ctxerrhelper/slog"
+
+	func main() {
+		logger := slog.New(ctxerrslog.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+		ctxerr.AddHandleHook(ctxerrslog.HandleHook(logger))
+		...
+	}
+*/
+package ctxerrslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+const (
+	// ErrorKey is the key used for the error attribute by HandleHook
+	ErrorKey = "error"
+	// MessageKey is the key used for the error message when an error attribute is expanded into a group
+	MessageKey = "message"
+)
+
+type contexter interface {
+	Context() context.Context
+}
+
+// NewHandler wraps a slog.Handler so that ctxerr fields are added to records logged with a context.
+func NewHandler(next slog.Handler) *Handler { return &Handler{next: next, root: next} }
+
+// Handler implements slog.Handler
+type Handler struct {
+	next slog.Handler
+	// root is next before the first WithGroup so context fields are added outside of any group
+	root slog.Handler
+	// grouped replays the groups and attributes added since the first WithGroup on top of root
+	grouped []func(slog.Handler) slog.Handler
+	// LogLevels are the levels that context fields are added for. Defaults to all levels
+	LogLevels []slog.Level
+	// Selection chooses which context fields are added. Defaults to all fields
+	Selection ctxerrfields.Selection
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds ctxerr fields from the context, expands ctxerr errors and passes the record to the wrapped handler.
+// The context fields are added at the root of the record, outside of any group opened with WithGroup.
+// Context fields that an error in the record already has are left out so they are only logged once, in the error group.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	var errorKeys []string
+	r.Attrs(func(a slog.Attr) bool {
+		errorKeys = appendErrorKeys(errorKeys, a)
+		nr.AddAttrs(ExpandError(a))
+		return true
+	})
+
+	if !h.enabled(r.Level) {
+		return h.next.Handle(ctx, nr)
+	}
+	fields := ctxerrfields.Selection{Exclude: errorKeys}.Select(h.Selection.Select(ctxerr.Fields(ctx)))
+	if len(fields) == 0 {
+		return h.next.Handle(ctx, nr)
+	}
+	if len(h.grouped) == 0 {
+		nr.AddAttrs(attrs(fields)...)
+		return h.next.Handle(ctx, nr)
+	}
+
+	next := h.root.WithAttrs(attrs(fields))
+	for _, g := range h.grouped {
+		next = g(next)
+	}
+	return next.Handle(ctx, nr)
+}
+
+// WithAttrs expands ctxerr errors in the attributes and passes them to the wrapped handler
+func (h *Handler) WithAttrs(as []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(as))
+	for i, a := range as {
+		expanded[i] = ExpandError(a)
+	}
+	if len(h.grouped) == 0 {
+		next := h.next.WithAttrs(expanded)
+		return &Handler{next: next, root: next, LogLevels: h.LogLevels, Selection: h.Selection}
+	}
+	return h.with(h.next.WithAttrs(expanded), func(next slog.Handler) slog.Handler { return next.WithAttrs(expanded) })
+}
+
+// WithGroup passes the group to the wrapped handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return h.with(h.next.WithGroup(name), func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+// with returns a copy of the handler with next and g added to the replayed groups
+func (h *Handler) with(next slog.Handler, g func(slog.Handler) slog.Handler) *Handler {
+	grouped := make([]func(slog.Handler) slog.Handler, len(h.grouped), len(h.grouped)+1)
+	copy(grouped, h.grouped)
+	return &Handler{next: next, root: h.root, grouped: append(grouped, g), LogLevels: h.LogLevels, Selection: h.Selection}
+}
+
+func (h *Handler) enabled(level slog.Level) bool {
+	if len(h.LogLevels) == 0 {
+		return true
+	}
+	for _, l := range h.LogLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandError turns an attribute holding an error with ctxerr fields into a group of the message and fields.
+// Any other attribute is returned unchanged.
+func ExpandError(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = ExpandError(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	case slog.KindAny:
+		err, ok := v.Any().(error)
+		if !ok || err == nil {
+			return a
+		}
+		fields := ctxerr.AllFields(err)
+		if len(fields) == 0 {
+			return a
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(append([]slog.Attr{slog.String(MessageKey, err.Error())}, attrs(fields)...)...)}
+	}
+	return a
+}
+
+// appendErrorKeys appends the field keys of the ctxerr errors in an attribute, including in groups
+func appendErrorKeys(keys []string, a slog.Attr) []string {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		for _, ga := range v.Group() {
+			keys = appendErrorKeys(keys, ga)
+		}
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok && err != nil {
+			for k := range ctxerr.AllFields(err) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// attrs converts fields to sanitized attributes sorted by key
+func attrs(fields map[string]any) []slog.Attr {
+	keys := ctxerrfields.SortedKeys(fields)
+	as := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		as[i] = slog.Any(k, ctxerrfields.Value(fields[k]))
+	}
+	return as
+}
+
+// HandleHook returns a hook that can be added to ctxerr.AddHandleHook to log handled errors at the error level
+func HandleHook(logger *slog.Logger) func(error) {
+	return func(err error) {
+		if err == nil {
+			return
+		}
+		ctx := context.Background()
+		if v, ok := err.(contexter); ok {
+			ctx = v.Context()
+		}
+		logger.ErrorContext(ctx, err.Error(), slog.Any(ErrorKey, err))
+	}
+}
@@ -0,0 +1,197 @@
+package ctxerrslog_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxerrslog "github.com/mvndaai/ctxerrhelper/slog"
+)
+
+func newLogger(sb *strings.Builder) (*slog.Logger, *ctxerrslog.Handler) {
+	h := ctxerrslog.NewHandler(slog.NewJSONHandler(sb, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return slog.New(h), h
+}
+
+func unmarshal(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal("could not unmarshall json", err, s)
+	}
+	return m
+}
+
+func TestHandler(t *testing.T) {
+	sb := &strings.Builder{}
+	lg, _ := newLogger(sb)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.InfoContext(ctx, "msg")
+
+	m := unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("could not find field in json", m)
+	}
+}
+
+func TestHandlerWithLogLevels(t *testing.T) {
+	sb := &strings.Builder{}
+	lg, h := newLogger(sb)
+	h.LogLevels = []slog.Level{slog.LevelWarn}
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.InfoContext(ctx, "msg")
+
+	m := unmarshal(t, sb.String())
+	if _, ok := m["foo"]; ok {
+		t.Error("fields should not have been added on info level:", m)
+	}
+
+	sb.Reset()
+	lg.With("a", "b").WarnContext(ctx, "msg")
+
+	m = unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("could not find field in json", m)
+	}
+	if m["a"] != "b" {
+		t.Error("could not find attribute in json", m)
+	}
+}
+
+func TestHandlerSelection(t *testing.T) {
+	sb := &strings.Builder{}
+	lg, h := newLogger(sb)
+	h.Selection = ctxerrfields.Selection{Exclude: []string{"secret"}}
+
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"foo": "bar", "secret": "s"})
+	lg.InfoContext(ctx, "msg")
+
+	m := unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("could not find field in json", m)
+	}
+	if _, ok := m["secret"]; ok {
+		t.Error("excluded field should not be added", m)
+	}
+}
+
+func TestHandlerWithGroup(t *testing.T) {
+	sb := &strings.Builder{}
+	lg, _ := newLogger(sb)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.With("a", "b").WithGroup("g").With("c", "d").InfoContext(ctx, "msg", "e", "f")
+
+	m := unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("field should be at the root", m)
+	}
+	if m["a"] != "b" {
+		t.Error("attribute before the group should be at the root", m)
+	}
+	g, ok := m["g"].(map[string]any)
+	if !ok {
+		t.Fatal("group was not found", m)
+	}
+	if _, ok := g["foo"]; ok {
+		t.Error("field should not be in the group", g)
+	}
+	if g["c"] != "d" || g["e"] != "f" {
+		t.Error("attributes should be in the group", g)
+	}
+}
+
+func TestExpandError(t *testing.T) {
+	ctxerrIn := ctxerr.Instance{}
+	ctxerrIn.AddCreateHook(ctxerr.SetCodeHook)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	err := ctxerrIn.New(ctx, "code", "msg")
+
+	tests := []struct {
+		name string
+		log  func(*slog.Logger)
+		path []string
+	}{
+		{name: "record", log: func(lg *slog.Logger) { lg.Info("msg", "err", err) }, path: []string{"err"}},
+		{name: "with", log: func(lg *slog.Logger) { lg.With("err", err).Info("msg") }, path: []string{"err"}},
+		{name: "group", log: func(lg *slog.Logger) { lg.Info("msg", slog.Group("g", "err", err)) }, path: []string{"g", "err"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			lg, _ := newLogger(sb)
+			tt.log(lg)
+
+			m := unmarshal(t, sb.String())
+			for _, p := range tt.path {
+				v, ok := m[p].(map[string]any)
+				if !ok {
+					t.Fatalf("%s was not expanded into a group\n%v", p, m)
+				}
+				m = v
+			}
+			if m[ctxerrslog.MessageKey] != "msg" {
+				t.Error("message did not match", m)
+			}
+			if m[ctxerr.FieldKeyCode] != "code" {
+				t.Error("code did not match", m)
+			}
+			if m["foo"] != "bar" {
+				t.Error("field did not match", m)
+			}
+		})
+	}
+}
+
+func TestExpandErrorNonCtxerr(t *testing.T) {
+	a := slog.Any("err", errors.New("plain"))
+	if out := ctxerrslog.ExpandError(a); !out.Equal(a) {
+		t.Error("non ctxerr errors should not be changed", out)
+	}
+}
+
+func TestHandleHook(t *testing.T) {
+	sb := &strings.Builder{}
+	lg, _ := newLogger(sb)
+
+	in := ctxerr.NewInstance()
+	in.AddHandleHook(ctxerrslog.HandleHook(lg))
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	in.Handle(in.New(ctx, "code", "msg"))
+
+	m := unmarshal(t, sb.String())
+	if m["level"] != "ERROR" {
+		t.Error("level should be error", m["level"])
+	}
+	if m["msg"] != "msg" {
+		t.Error("message did not match", m["msg"])
+	}
+	e, ok := m[ctxerrslog.ErrorKey].(map[string]any)
+	if !ok {
+		t.Fatal("error was not expanded", m)
+	}
+	if e["foo"] != "bar" {
+		t.Error("field did not match", e)
+	}
+	if _, ok := m["foo"]; ok {
+		t.Error("field of the error should only be in the error group", m)
+	}
+}
+
+func ExampleNewHandler() {
+	lg := slog.New(ctxerrslog.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.InfoContext(ctx, "msg")
+}
@@ -0,0 +1,5 @@
+module github.com/mvndaai/ctxerrhelper/traceid
+
+go 1.18
+
+require github.com/mvndaai/ctxerr v0.13.0
@@ -0,0 +1,95 @@
+package traceid
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderTraceparent is the W3C Trace Context header, see https://www.w3.org/TR/trace-context/#traceparent-header
+	HeaderTraceparent = "traceparent"
+	// HeaderB3 is the B3 single header, see https://github.com/openzipkin/b3-propagation
+	HeaderB3 = "b3"
+	// HeaderB3TraceID is the B3 multi header trace ID
+	HeaderB3TraceID = "X-B3-TraceId"
+	// HeaderXRequestID is used as the trace ID when there are no tracing headers
+	HeaderXRequestID = "X-Request-Id"
+)
+
+// Middleware is a net/http middleware that stores the trace ID from the request headers on the context
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := FromHeader(r.Header); id != "" {
+			r = r.WithContext(NewContext(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FromHeader returns the trace ID from the first valid header of traceparent, b3, X-B3-TraceId and X-Request-Id
+func FromHeader(h http.Header) string {
+	if id, ok := ParseTraceparent(h.Get(HeaderTraceparent)); ok {
+		return id
+	}
+	if id, ok := ParseB3(h.Get(HeaderB3)); ok {
+		return id
+	}
+	if id, ok := ParseB3TraceID(h.Get(HeaderB3TraceID)); ok {
+		return id
+	}
+	return strings.TrimSpace(h.Get(HeaderXRequestID))
+}
+
+// ParseTraceparent returns the trace ID from a W3C traceparent header value like
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(v string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 {
+		return "", false
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", false
+	}
+	if !isHex(traceID, 32) || !isHex(parentID, 16) || !isHex(flags, 2) || isZero(traceID) || isZero(parentID) {
+		return "", false
+	}
+	return traceID, true
+}
+
+// ParseB3 returns the trace ID from a B3 single header value like
+// 80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1. A value that only has the sampling state has no trace ID.
+func ParseB3(v string) (string, bool) {
+	traceID, _, ok := strings.Cut(strings.TrimSpace(v), "-")
+	if !ok {
+		return "", false
+	}
+	return ParseB3TraceID(traceID)
+}
+
+// ParseB3TraceID returns a 16 or 32 character X-B3-TraceId header value in lower case
+func ParseB3TraceID(v string) (string, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if (!isHex(v, 16) && !isHex(v, 32)) || isZero(v) {
+		return "", false
+	}
+	return v, true
+}
+
+// isHex tells if s is n lower case hex characters
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero tells if s only has zeros, which is an invalid ID
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
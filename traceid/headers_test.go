@@ -0,0 +1,148 @@
+package traceid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mvndaai/ctxerrhelper/traceid"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{name: "valid", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expected: "4bf92f3577b34da6a3ce929d0e0e4736", ok: true},
+		{name: "not sampled", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", expected: "4bf92f3577b34da6a3ce929d0e0e4736", ok: true},
+		{name: "future version", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", expected: "4bf92f3577b34da6a3ce929d0e0e4736", ok: true},
+		{name: "empty", value: ""},
+		{name: "extra parts in version 00", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "invalid version", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "upper case", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "zero trace ID", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "zero parent ID", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "short trace ID", value: "00-4bf92f3577b34da6-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := traceid.ParseTraceparent(tt.value)
+			if id != tt.expected || ok != tt.ok {
+				t.Errorf("did not match [%s %v] [%s %v]", id, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseB3(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{name: "valid", value: "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90", expected: "80f198ee56343ba864fe8b2a57d3eff7", ok: true},
+		{name: "64 bit", value: "e457b5a2e4d86bd1-e457b5a2e4d86bd1", expected: "e457b5a2e4d86bd1", ok: true},
+		{name: "sampling only", value: "1"},
+		{name: "invalid", value: "xyz-e457b5a2e4d86bd1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := traceid.ParseB3(tt.value)
+			if id != tt.expected || ok != tt.ok {
+				t.Errorf("did not match [%s %v] [%s %v]", id, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseB3TraceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{name: "128 bit", value: "80f198ee56343ba864fe8b2a57d3eff7", expected: "80f198ee56343ba864fe8b2a57d3eff7", ok: true},
+		{name: "64 bit upper case", value: "E457B5A2E4D86BD1", expected: "e457b5a2e4d86bd1", ok: true},
+		{name: "zero", value: "0000000000000000"},
+		{name: "wrong length", value: "80f198ee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := traceid.ParseB3TraceID(tt.value)
+			if id != tt.expected || ok != tt.ok {
+				t.Errorf("did not match [%s %v] [%s %v]", id, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	b3 := "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{name: "none", expected: ""},
+		{
+			name:     "traceparent first",
+			headers:  map[string]string{traceid.HeaderTraceparent: traceparent, traceid.HeaderB3: b3, traceid.HeaderXRequestID: "request"},
+			expected: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:     "invalid traceparent falls back",
+			headers:  map[string]string{traceid.HeaderTraceparent: "bad", traceid.HeaderB3: b3},
+			expected: "80f198ee56343ba864fe8b2a57d3eff7",
+		},
+		{
+			name:     "b3 multi",
+			headers:  map[string]string{traceid.HeaderB3TraceID: "e457b5a2e4d86bd1", traceid.HeaderXRequestID: "request"},
+			expected: "e457b5a2e4d86bd1",
+		},
+		{
+			name:     "request ID",
+			headers:  map[string]string{traceid.HeaderXRequestID: "request"},
+			expected: "request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			if out := traceid.FromHeader(h); out != tt.expected {
+				t.Error("trace ID did not match", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var out string
+	h := traceid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out = traceid.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(traceid.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if out != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Error("trace ID did not match", out)
+	}
+	if out := traceid.FromContext(context.Background()); out != "" {
+		t.Error("trace ID should be empty without the middleware", out)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Package traceid sets http.TraceID from a chain of trace ID extractors so multiple tracing providers can be used
+without overwriting each other. Install returns a function that restores the previous http.TraceID, which is useful in tests.
+
+	import (
+		"github.com/mvndaai/ctxerrhelper/opencensus"
+		ctxotel "github.com/mvndaai/ctxerrhelper/otel"
+		"github.com/mvndaai/ctxerrhelper/traceid"
+	)
+
+	func main() {
+		traceid.Install(opencensus.TraceID, ctxotel.TraceID, traceid.FromContext)
+		...
+	}
+
+Services without a tracer can use Middleware to read the trace ID from the traceparent, B3 or X-Request-Id
+request headers and install FromContext. The framework packages have their own middlewares that store the same trace ID.
+
+	traceid.Install(traceid.FromContext)
+	http.ListenAndServe(":8080", traceid.Middleware(mux))
+*/
+package traceid
+
+import (
+	"context"
+
+	"github.com/mvndaai/ctxerr/http"
+)
+
+// Extractor gets a trace ID from a context and returns an empty string if there is none
+type Extractor func(ctx context.Context) string
+
+// Chain returns an Extractor that returns the first trace ID found by the extractors in order
+func Chain(extractors ...Extractor) Extractor {
+	return func(ctx context.Context) string {
+		for _, e := range extractors {
+			if e == nil {
+				continue
+			}
+			if id := e(ctx); id != "" {
+				return id
+			}
+		}
+		return ""
+	}
+}
+
+// Install replaces http.TraceID with a Chain of the extractors and returns a function that restores the previous value
+func Install(extractors ...Extractor) (restore func()) {
+	previous := http.TraceID
+	http.TraceID = Chain(extractors...)
+	return func() { http.TraceID = previous }
+}
+
+type contextKey struct{}
+
+// NewContext stores a trace ID, like one from a traceparent, B3 or X-Request-Id request header, on the context
+func NewContext(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, traceID)
+}
+
+// FromContext returns the trace ID stored with NewContext
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,57 @@
+package traceid_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvndaai/ctxerr/http"
+	"github.com/mvndaai/ctxerrhelper/traceid"
+)
+
+func TestChain(t *testing.T) {
+	empty := func(context.Context) string { return "" }
+	first := func(context.Context) string { return "first" }
+	second := func(context.Context) string { return "second" }
+
+	tests := []struct {
+		name       string
+		extractors []traceid.Extractor
+		expected   string
+	}{
+		{name: "none", expected: ""},
+		{name: "empty", extractors: []traceid.Extractor{empty}, expected: ""},
+		{name: "order", extractors: []traceid.Extractor{first, second}, expected: "first"},
+		{name: "skip empty", extractors: []traceid.Extractor{empty, nil, second}, expected: "second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := traceid.Chain(tt.extractors...)(context.Background()); out != tt.expected {
+				t.Error("trace ID did not match", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	ctx := traceid.NewContext(context.Background(), "header")
+
+	restore := traceid.Install(func(context.Context) string { return "" }, traceid.FromContext)
+	if out := http.TraceID(ctx); out != "header" {
+		t.Error("trace ID did not match", out)
+	}
+
+	restore()
+	if out := http.TraceID(ctx); out == "header" {
+		t.Error("http.TraceID should be restored")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if out := traceid.FromContext(context.Background()); out != "" {
+		t.Error("trace ID should be empty", out)
+	}
+	if out := traceid.FromContext(traceid.NewContext(context.Background(), "id")); out != "id" {
+		t.Error("trace ID did not match", out)
+	}
+}
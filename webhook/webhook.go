@@ -0,0 +1,217 @@
+/*
+Package webhook sends handled ctxerr errors as JSON to any webhook.
+The body is built with a text/template or a function and requests can be signed with HMAC.
+
+	import "github.com/mvndaai/ctxerrhelper/webhook"
+
+	func main() {
+		tmpl, err := webhook.ParseTemplate(`{"summary": {{json .Message}}, "code": {{json .Code}}}`)
+		...
+		conf := webhook.Config{
+			HTTPClient: &http.Client{Timeout: 10 * time.Second},
+			URL:        "https://alerts.example.com/hooks/errors",
+			Template:   tmpl,
+			Secret:     config.WebhookSecret,
+		}
+		ctxerr.AddHandleHook(conf.HandleHook)
+		...
+	}
+*/
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+)
+
+const (
+	// DefaultSignatureHeader is the header the HMAC signature is sent in when Config.SignatureHeader is empty
+	DefaultSignatureHeader = "X-Signature-256"
+	// SignaturePrefix is prepended to the hex encoded HMAC-SHA256 signature
+	SignaturePrefix = "sha256="
+)
+
+// Config configures where and how errors are sent
+type Config struct {
+	// HTTPClient is used to make requests to the webhook. It is required so a timeout can be set
+	HTTPClient *http.Client
+	// URL is the webhook url
+	URL string
+	// Method is the http method of the request. Defaults to POST
+	Method string
+	// Headers are added to every request
+	Headers map[string]string
+	// Template builds the body from Data. It takes precedence over Payload. Use ParseTemplate to get the json function
+	Template *template.Template
+	// Payload builds a value that is marshaled to JSON as the body. Defaults to a Payload using Fields and Selection
+	Payload func(error) any
+	// Secret signs the body with HMAC-SHA256 when set
+	Secret string
+	// SignatureHeader is the header the signature is sent in. Defaults to DefaultSignatureHeader
+	SignatureHeader string
+	// Ignore tells if an error should be ignored and not sent
+	Ignore func(error) bool
+	// LogError is a way to log an error not using ctxerr.Handle to avoid circular errors
+	LogError func(error)
+	// Fields function to get fields for the body. Defaults to ctxerr.AllFields
+	Fields func(error) map[string]any
+	// Selection chooses which of the fields are sent. Defaults to all fields
+	Selection ctxerrfields.Selection
+}
+
+// Data is passed to the Template
+type Data struct {
+	Error   error
+	Message string
+	Code    string
+	Fields  map[string]any
+}
+
+// Payload is the body sent by DefaultPayload
+type Payload struct {
+	Message string         `json:"message"`
+	Code    string         `json:"code,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// DefaultPayload returns a Payload with the error message, code and all sanitized fields
+func DefaultPayload(err error) any {
+	return Config{}.defaultPayload(err)
+}
+
+func (c Config) defaultPayload(err error) any {
+	d := c.data(err)
+	return Payload{Message: d.Message, Code: d.Code, Fields: d.Fields}
+}
+
+// ParseTemplate parses a template with a json function for writing values as JSON, like {{json .Message}}
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (c Config) data(err error) Data {
+	ff := c.Fields
+	if ff == nil {
+		ff = ctxerr.AllFields
+	}
+	fields := ctxerrfields.Sanitize(c.Selection.Select(ff(err)))
+
+	d := Data{Error: err, Message: err.Error(), Fields: fields}
+	if code, ok := ctxerr.AllFields(err)[ctxerr.FieldKeyCode]; ok {
+		d.Code = fmt.Sprint(code)
+	}
+	return d
+}
+
+// Body builds the request body for an error with the Template or Payload.
+// An error is returned if the Template output is not valid JSON
+func (c Config) Body(err error) ([]byte, error) {
+	if err == nil {
+		return nil, fmt.Errorf("nil error")
+	}
+	if c.Template != nil {
+		var buf bytes.Buffer
+		if tmplErr := c.Template.Execute(&buf, c.data(err)); tmplErr != nil {
+			return nil, tmplErr
+		}
+		if !json.Valid(buf.Bytes()) {
+			return nil, fmt.Errorf("template output is not valid JSON")
+		}
+		return buf.Bytes(), nil
+	}
+
+	payload := c.Payload
+	if payload == nil {
+		payload = c.defaultPayload
+	}
+	return json.Marshal(payload(err))
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body with the SignaturePrefix
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send sends the body to the webhook. Failures, including non 2xx responses, are passed to LogError
+func (c Config) Send(body []byte) {
+	if c.URL == "" {
+		c.logError(fmt.Errorf("no URL"))
+		return
+	}
+	if c.HTTPClient == nil {
+		c.logError(fmt.Errorf("nil HTTPClient"))
+		return
+	}
+
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, c.URL, bytes.NewReader(body))
+	if err != nil {
+		c.logError(err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+	if c.Secret != "" {
+		header := c.SignatureHeader
+		if header == "" {
+			header = DefaultSignatureHeader
+		}
+		req.Header.Set(header, Sign(c.Secret, body))
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		c.logError(err)
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.logError(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+	}
+}
+
+func (c Config) logError(err error) {
+	if c.LogError != nil {
+		c.LogError(err)
+	}
+}
+
+// HandleHook is a hook that can be added to ctxerr.AddHandleHook
+func (c Config) HandleHook(err error) {
+	if err == nil {
+		return
+	}
+	if c.Ignore != nil && c.Ignore(err) {
+		return
+	}
+	body, bodyErr := c.Body(err)
+	if bodyErr != nil {
+		c.logError(bodyErr)
+		return
+	}
+	c.Send(body)
+}
@@ -0,0 +1,190 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"github.com/mvndaai/ctxerrhelper/webhook"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, chan request) {
+	t.Helper()
+	requests := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: b}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func TestHandleHookDefaultPayload(t *testing.T) {
+	srv, requests := newServer(t, http.StatusOK)
+
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"foo": "bar", "baz": "qux"})
+	err := ctxerr.New(ctx, "code", "message")
+
+	c := webhook.Config{
+		HTTPClient: srv.Client(),
+		URL:        srv.URL,
+		Selection:  ctxerrfields.Selection{Include: []string{"foo"}},
+		LogError:   func(err error) { t.Error("unexpected error", err) },
+	}
+	c.HandleHook(err)
+
+	r := <-requests
+	if r.header.Get("Content-Type") != "application/json" {
+		t.Error("content type should be json", r.header.Get("Content-Type"))
+	}
+	var p webhook.Payload
+	if err := json.Unmarshal(r.body, &p); err != nil {
+		t.Fatal("could not unmarshal body", err, string(r.body))
+	}
+	expected := webhook.Payload{Message: err.Error(), Code: "code", Fields: map[string]any{"foo": "bar"}}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("payload did not match\n%#v\n%#v", p, expected)
+	}
+}
+
+func TestHandleHookPayload(t *testing.T) {
+	srv, requests := newServer(t, http.StatusOK)
+
+	c := webhook.Config{
+		HTTPClient: srv.Client(),
+		URL:        srv.URL,
+		Method:     http.MethodPut,
+		Payload:    func(err error) any { return map[string]string{"text": err.Error()} },
+	}
+	c.HandleHook(errors.New("boom"))
+
+	r := <-requests
+	if string(r.body) != `{"text":"boom"}` {
+		t.Error("body did not match", string(r.body))
+	}
+}
+
+func TestHandleHookTemplate(t *testing.T) {
+	srv, requests := newServer(t, http.StatusOK)
+
+	tmpl, err := webhook.ParseTemplate(`{"summary":{{json .Message}},"code":{{json .Code}},"foo":{{json .Fields.foo}}}`)
+	if err != nil {
+		t.Fatal("could not parse template", err)
+	}
+	ctx := ctxerr.SetField(context.Background(), "foo", `"quoted"`)
+	c := webhook.Config{HTTPClient: srv.Client(), URL: srv.URL, Template: tmpl}
+	c.HandleHook(ctxerr.New(ctx, "code", "message"))
+
+	r := <-requests
+	var m map[string]any
+	if err := json.Unmarshal(r.body, &m); err != nil {
+		t.Fatal("template should produce valid json", err, string(r.body))
+	}
+	if m["code"] != "code" || m["foo"] != `"quoted"` || m["summary"] == "" {
+		t.Error("body did not match", m)
+	}
+}
+
+func TestHandleHookHeadersAndSignature(t *testing.T) {
+	srv, requests := newServer(t, http.StatusOK)
+
+	c := webhook.Config{
+		HTTPClient: srv.Client(),
+		URL:        srv.URL,
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+		Secret:     "secret",
+	}
+	c.HandleHook(errors.New("boom"))
+
+	r := <-requests
+	if r.header.Get("Authorization") != "Bearer token" {
+		t.Error("custom header missing", r.header)
+	}
+	if sig := r.header.Get(webhook.DefaultSignatureHeader); sig != webhook.Sign("secret", r.body) {
+		t.Error("signature did not match", sig)
+	}
+
+	c.SignatureHeader = "X-Hub-Signature"
+	c.HandleHook(errors.New("boom"))
+	r = <-requests
+	if r.header.Get("X-Hub-Signature") == "" || r.header.Get(webhook.DefaultSignatureHeader) != "" {
+		t.Error("signature should use the configured header", r.header)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n 'body' | openssl dgst -sha256 -hmac 'secret'
+	expected := "sha256=dc46983557fea127b43af721467eb9b3fde2338fe3e14f51952aa8478c13d355"
+	if got := webhook.Sign("secret", []byte("body")); got != expected {
+		t.Error("signature did not match", got)
+	}
+}
+
+func TestHandleHookIgnore(t *testing.T) {
+	srv, requests := newServer(t, http.StatusOK)
+
+	c := webhook.Config{HTTPClient: srv.Client(), URL: srv.URL, Ignore: func(error) bool { return true }}
+	c.HandleHook(errors.New("boom"))
+
+	select {
+	case r := <-requests:
+		t.Error("ignored error should not be sent", string(r.body))
+	default:
+	}
+}
+
+func TestHandleHookLogError(t *testing.T) {
+	srv, requests := newServer(t, http.StatusInternalServerError)
+
+	var logged []error
+	c := webhook.Config{HTTPClient: srv.Client(), URL: srv.URL, LogError: func(err error) { logged = append(logged, err) }}
+	c.HandleHook(errors.New("boom"))
+	<-requests
+	if len(logged) != 1 {
+		t.Error("non 2xx response should be logged", logged)
+	}
+
+	logged = nil
+	webhook.Config{HTTPClient: srv.Client(), LogError: c.LogError}.HandleHook(errors.New("boom"))
+	if len(logged) != 1 {
+		t.Error("missing URL should be logged", logged)
+	}
+
+	logged = nil
+	webhook.Config{URL: srv.URL, LogError: c.LogError}.HandleHook(errors.New("boom"))
+	if len(logged) != 1 {
+		t.Error("nil HTTPClient should be logged", logged)
+	}
+
+	logged = nil
+	tmpl, _ := webhook.ParseTemplate(`{{.Missing.Field}}`)
+	webhook.Config{HTTPClient: srv.Client(), URL: srv.URL, Template: tmpl, LogError: c.LogError}.HandleHook(errors.New("boom"))
+	if len(logged) != 1 {
+		t.Error("template error should be logged", logged)
+	}
+
+	logged = nil
+	tmpl, _ = webhook.ParseTemplate(`{"summary": {{.Message}}}`)
+	webhook.Config{HTTPClient: srv.Client(), URL: srv.URL, Template: tmpl, LogError: c.LogError}.HandleHook(errors.New("boom"))
+	if len(logged) != 1 {
+		t.Error("invalid json from the template should be logged", logged)
+	}
+	select {
+	case r := <-requests:
+		t.Error("invalid json should not be sent", string(r.body))
+	default:
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Package ctxerrzap has helpers to use ctxerr with zap (https://pkg.go.dev/go.uber.org/zap).
+
+	import ctxerrzap "github.com/mvndaai/ctxerrhelper/zap"
+
+	func main() {
+		logger := zap.New(ctxerrzap.NewCore(core))
+		ctxerr.AddHandleHook(ctxerrzap.HandleHook(logger))
+		...
+		logger.Info("msg", ctxerrzap.Context(ctx))
+		logger.Warn("msg", ctxerrzap.Error(err))
+	}
+*/
+package ctxerrzap
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// ErrorKey is the key used by Error
+	ErrorKey = "error"
+	// MessageKey is the key of the error message in the Error object
+	MessageKey = "message"
+	// CodeKey is the key of the ctxerr code in the Error object
+	CodeKey = "code"
+	// StatusCodeKey is the key of the ctxerr status code in the Error object
+	StatusCodeKey = "status_code"
+	// FieldsKey is the key of the ctxerr fields in the Error object
+	FieldsKey = "fields"
+)
+
+// contextKey is the key of fields created by Context, it is never encoded
+const contextKey = "ctxerr.context"
+
+// Context returns a field carrying the context. A Core from NewCore replaces it with the ctxerr fields of the context.
+// Without that Core the field is skipped.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// Fields converts the ctxerr fields in the context to zap fields sorted by key
+func Fields(ctx context.Context) []zap.Field {
+	return zapFields(ctxerr.Fields(ctx))
+}
+
+// SelectedFields converts the ctxerr fields in the context chosen by the selection to zap fields sorted by key
+func SelectedFields(ctx context.Context, selection ctxerrfields.Selection) []zap.Field {
+	return zapFields(selection.Select(ctxerr.Fields(ctx)))
+}
+
+func zapFields(fields map[string]any) []zap.Field {
+	keys := sortedKeys(fields)
+	zfs := make([]zap.Field, len(keys))
+	for i, k := range keys {
+		zfs[i] = zap.Any(k, ctxerrfields.Value(fields[k]))
+	}
+	return zfs
+}
+
+// NewCore wraps a zapcore.Core so fields from Context are replaced with the ctxerr fields of the context
+func NewCore(core zapcore.Core) zapcore.Core { return &Core{Core: core} }
+
+// Core implements zapcore.Core
+type Core struct {
+	zapcore.Core
+	// Selection chooses which context fields are added. Defaults to all fields
+	Selection ctxerrfields.Selection
+}
+
+// With adds structured context to the wrapped core
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{Core: c.Core.With(c.expand(fields)), Selection: c.Selection}
+}
+
+// Check adds this core to the checked entry if the level is enabled.
+// The wrapped core is checked in Write so it can still decide to drop the entry, like a sampler or tee does.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write expands Context fields and writes them through the checked entry of the wrapped core
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ce := c.Core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	var wes writeErrors
+	ce.ErrorOutput = &wes
+	ce.Write(c.expand(fields)...)
+	return wes.err()
+}
+
+// writeErrors collects the errors a zapcore.CheckedEntry reports to its ErrorOutput so Write can return them
+type writeErrors []string
+
+func (w *writeErrors) Write(b []byte) (int, error) {
+	*w = append(*w, strings.TrimSpace(string(b)))
+	return len(b), nil
+}
+
+func (w *writeErrors) Sync() error { return nil }
+
+func (w writeErrors) err() error {
+	if len(w) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(w, "; "))
+}
+
+// expand replaces Context fields with the selected ctxerr fields of their context
+func (c *Core) expand(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		ctx, ok := f.Interface.(context.Context)
+		if f.Key != contextKey || !ok {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		out = append(out, SelectedFields(ctx, c.Selection)...)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// Error returns a field with the error message, ctxerr code, status code and all ctxerr fields as an object
+func Error(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(ErrorKey, errorObject{err: err})
+}
+
+type errorObject struct {
+	err error
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (eo errorObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(MessageKey, eo.err.Error())
+
+	fields := ctxerr.AllFields(eo.err)
+	if v, ok := fields[ctxerr.FieldKeyCode]; ok {
+		if err := enc.AddReflected(CodeKey, v); err != nil {
+			return err
+		}
+	}
+	if v, ok := fields[ctxerr.FieldKeyStatusCode]; ok {
+		if err := enc.AddReflected(StatusCodeKey, v); err != nil {
+			return err
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return enc.AddObject(FieldsKey, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, k := range sortedKeys(fields) {
+			zap.Any(k, ctxerrfields.Value(fields[k])).AddTo(enc)
+		}
+		return nil
+	}))
+}
+
+// HandleHook returns a hook that can be added to ctxerr.AddHandleHook to log handled errors at the error level
+func HandleHook(logger *zap.Logger) func(error) {
+	return func(err error) {
+		if err == nil {
+			return
+		}
+		logger.Error(err.Error(), Error(err))
+	}
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,220 @@
+package ctxerrzap_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	ctxerrzap "github.com/mvndaai/ctxerrhelper/zap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newLogger() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(ctxerrzap.NewCore(core)), logs
+}
+
+func TestContext(t *testing.T) {
+	lg, logs := newLogger()
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info("msg", zap.String("a", "b"), ctxerrzap.Context(ctx))
+	lg.With(ctxerrzap.Context(ctx)).Info("msg")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatal("expected 2 entries", len(entries))
+	}
+	for _, e := range entries {
+		m := e.ContextMap()
+		if m["foo"] != "bar" {
+			t.Error("could not find field", m)
+		}
+	}
+	if v := entries[0].ContextMap()["a"]; v != "b" {
+		t.Error("other fields should be kept", v)
+	}
+}
+
+func TestContextWithoutCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lg := zap.New(core)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info("msg", ctxerrzap.Context(ctx))
+
+	if m := logs.All()[0].ContextMap(); len(m) != 0 {
+		t.Error("context field should be skipped", m)
+	}
+}
+
+func TestCoreSelection(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lg := zap.New(&ctxerrzap.Core{Core: core, Selection: ctxerrfields.Selection{Exclude: []string{"baz"}}})
+
+	ctx := ctxerr.SetFields(context.Background(), map[string]interface{}{"foo": "bar", "baz": "qux"})
+	lg.Info("msg", ctxerrzap.Context(ctx))
+	lg.With(ctxerrzap.Context(ctx)).Info("msg")
+
+	for _, e := range logs.All() {
+		if m := e.ContextMap(); !reflect.DeepEqual(m, map[string]interface{}{"foo": "bar"}) {
+			t.Error("only selected fields should be added", m)
+		}
+	}
+
+	if zfs := ctxerrzap.SelectedFields(ctx, ctxerrfields.Selection{Include: []string{"baz"}}); len(zfs) != 1 || zfs[0].Key != "baz" {
+		t.Error("only selected fields should be converted", zfs)
+	}
+}
+
+func TestCoreTee(t *testing.T) {
+	debugCore, debugLogs := observer.New(zapcore.DebugLevel)
+	errorCore, errorLogs := observer.New(zapcore.ErrorLevel)
+	lg := zap.New(ctxerrzap.NewCore(zapcore.NewTee(debugCore, errorCore)))
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info("msg", ctxerrzap.Context(ctx))
+
+	if debugLogs.Len() != 1 {
+		t.Fatal("debug core should have the entry", debugLogs.Len())
+	}
+	if m := debugLogs.All()[0].ContextMap(); m["foo"] != "bar" {
+		t.Error("could not find field", m)
+	}
+	if errorLogs.Len() != 0 {
+		t.Error("error core should not have an info entry", errorLogs.All())
+	}
+}
+
+func TestCoreSampler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lg := zap.New(ctxerrzap.NewCore(zapcore.NewSamplerWithOptions(core, time.Minute, 1, 0)))
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	for i := 0; i < 3; i++ {
+		lg.Info("msg", ctxerrzap.Context(ctx))
+	}
+
+	if logs.Len() != 1 {
+		t.Error("sampler should drop repeated entries", logs.Len())
+	}
+}
+
+type failingSyncer struct{}
+
+func (failingSyncer) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+func (failingSyncer) Sync() error               { return nil }
+
+func TestCoreWriteError(t *testing.T) {
+	inner := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), failingSyncer{}, zapcore.DebugLevel)
+	core := ctxerrzap.NewCore(inner)
+
+	err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "msg"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "write failed") {
+		t.Error("write error should be returned", err)
+	}
+}
+
+func TestError(t *testing.T) {
+	ctxerrIn := ctxerr.Instance{}
+	ctxerrIn.AddCreateHook(ctxerr.SetCodeHook)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected map[string]any
+	}{
+		{
+			name:     "go error",
+			err:      errors.New("msg"),
+			expected: map[string]any{ctxerrzap.MessageKey: "msg"},
+		},
+		{
+			name: "ctxerr",
+			err:  ctxerrIn.NewHTTP(ctx, "code", "", 400, "msg"),
+			expected: map[string]any{
+				ctxerrzap.MessageKey:    "msg",
+				ctxerrzap.CodeKey:       "code",
+				ctxerrzap.StatusCodeKey: 400,
+				ctxerrzap.FieldsKey: map[string]any{
+					"foo":                     "bar",
+					ctxerr.FieldKeyCode:       "code",
+					ctxerr.FieldKeyStatusCode: int64(400),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			ctxerrzap.Error(tt.err).AddTo(enc)
+
+			m, ok := enc.Fields[ctxerrzap.ErrorKey].(map[string]any)
+			if !ok {
+				t.Fatal("error was not an object", enc.Fields)
+			}
+			if len(m) != len(tt.expected) {
+				t.Errorf("fields did not match\n%v\n%v", m, tt.expected)
+			}
+			for k, v := range tt.expected {
+				if fields, ok := v.(map[string]any); ok {
+					got, _ := m[k].(map[string]any)
+					for fk, fv := range fields {
+						if got[fk] != fv {
+							t.Errorf("field %s did not match %v %v", fk, got[fk], fv)
+						}
+					}
+					continue
+				}
+				if m[k] != v {
+					t.Errorf("%s did not match %v %v", k, m[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorNil(t *testing.T) {
+	if f := ctxerrzap.Error(nil); f.Type != zapcore.SkipType {
+		t.Error("nil error should be skipped", f)
+	}
+}
+
+func TestHandleHook(t *testing.T) {
+	lg, logs := newLogger()
+
+	in := ctxerr.NewInstance()
+	in.AddHandleHook(ctxerrzap.HandleHook(lg))
+	in.Handle(in.New(context.Background(), "code", "msg"))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatal("expected 1 entry", len(entries))
+	}
+	if entries[0].Level != zapcore.ErrorLevel {
+		t.Error("level should be error", entries[0].Level)
+	}
+	if entries[0].Message != "msg" {
+		t.Error("message did not match", entries[0].Message)
+	}
+	if _, ok := entries[0].ContextMap()[ctxerrzap.ErrorKey]; !ok {
+		t.Error("error field missing", entries[0].ContextMap())
+	}
+}
+
+func ExampleContext() {
+	lg := zap.New(ctxerrzap.NewCore(zapcore.NewNopCore()))
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info("msg", ctxerrzap.Context(ctx))
+}
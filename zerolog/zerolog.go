@@ -0,0 +1,114 @@
+/*
+Package ctxerrzerolog has helpers to use ctxerr with zerolog (https://pkg.go.dev/github.com/rs/zerolog).
+
+	import ctxerrzerolog "github.com/mvndaai/ctxerrhelper/zerolog"
+
+	func main() {
+		zerolog.ErrorMarshalFunc = ctxerrzerolog.ErrorMarshalFunc
+		logger := zerolog.New(os.Stdout).Hook(ctxerrzerolog.NewContextHook())
+		ctxerr.AddHandleHook(ctxerrzerolog.HandleHook(logger))
+		...
+		logger.Info().Ctx(ctx).Msg("msg")
+		logger.Warn().Err(err).Msg("msg")
+	}
+*/
+package ctxerrzerolog
+
+import (
+	"github.com/mvndaai/ctxerr"
+	ctxerrfields "github.com/mvndaai/ctxerrhelper/fields"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// MessageKey is the key of the error message in the error object
+	MessageKey = "message"
+	// CodeKey is the key of the ctxerr code in the error object
+	CodeKey = "code"
+	// StatusCodeKey is the key of the ctxerr status code in the error object
+	StatusCodeKey = "status_code"
+	// FieldsKey is the key of the ctxerr fields in the error object
+	FieldsKey = "fields"
+)
+
+// NewContextHook creates a zerolog hook that adds ctxerr fields to events with a context.
+func NewContextHook() *ContextHook { return &ContextHook{} }
+
+// ContextHook implements zerolog.Hook
+type ContextHook struct {
+	// LogLevels are the levels that context fields are added for. Defaults to all levels
+	LogLevels []zerolog.Level
+	// Selection chooses which context fields are added. Defaults to all fields
+	Selection ctxerrfields.Selection
+}
+
+// Run adds the ctxerr fields from the event context to the event
+func (hook ContextHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	if !hook.enabled(level) {
+		return
+	}
+	if fields := hook.Selection.Select(ctxerr.Fields(e.GetCtx())); len(fields) > 0 {
+		e.Fields(ctxerrfields.Sanitize(fields))
+	}
+}
+
+func (hook ContextHook) enabled(level zerolog.Level) bool {
+	if len(hook.LogLevels) == 0 {
+		return true
+	}
+	for _, l := range hook.LogLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Object returns a zerolog.LogObjectMarshaler with the error message, ctxerr code, status code and the other ctxerr fields
+func Object(err error) zerolog.LogObjectMarshaler {
+	return errorObject{err: err}
+}
+
+type errorObject struct {
+	err error
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler
+func (eo errorObject) MarshalZerologObject(e *zerolog.Event) {
+	if eo.err == nil {
+		return
+	}
+	e.Str(MessageKey, eo.err.Error())
+
+	fields := ctxerr.AllFields(eo.err)
+	if v, ok := fields[ctxerr.FieldKeyCode]; ok {
+		e.Interface(CodeKey, v)
+	}
+	if v, ok := fields[ctxerr.FieldKeyStatusCode]; ok {
+		e.Interface(StatusCodeKey, v)
+	}
+	fields = ctxerrfields.Selection{Exclude: []string{ctxerr.FieldKeyCode, ctxerr.FieldKeyStatusCode}}.Select(fields)
+	if len(fields) > 0 {
+		e.Dict(FieldsKey, zerolog.Dict().Fields(ctxerrfields.Sanitize(fields)))
+	}
+}
+
+// ErrorMarshalFunc can replace zerolog.ErrorMarshalFunc so errors with ctxerr fields are logged as objects
+func ErrorMarshalFunc(err error) interface{} {
+	if len(ctxerr.AllFields(err)) > 0 {
+		return Object(err)
+	}
+	return err
+}
+
+// HandleHook returns a hook that can be added to ctxerr.AddHandleHook to log handled errors at the error level.
+// The context of the error is not set on the event because its fields are already in the error object,
+// so a ContextHook does not add them again at the top level.
+func HandleHook(logger zerolog.Logger) func(error) {
+	return func(err error) {
+		if err == nil {
+			return
+		}
+		logger.Error().Object(zerolog.ErrorFieldName, Object(err)).Msg(err.Error())
+	}
+}
@@ -0,0 +1,153 @@
+package ctxerrzerolog_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mvndaai/ctxerr"
+	ctxerrzerolog "github.com/mvndaai/ctxerrhelper/zerolog"
+	"github.com/rs/zerolog"
+)
+
+func unmarshal(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal("could not unmarshall json", err, s)
+	}
+	return m
+}
+
+func TestHook(t *testing.T) {
+	sb := &strings.Builder{}
+	lg := zerolog.New(sb).Hook(ctxerrzerolog.NewContextHook())
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info().Ctx(ctx).Msg("msg")
+
+	m := unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("could not find field in json", m)
+	}
+}
+
+func TestHookWithLogLevels(t *testing.T) {
+	sb := &strings.Builder{}
+	hook := ctxerrzerolog.NewContextHook()
+	hook.LogLevels = []zerolog.Level{zerolog.WarnLevel}
+	lg := zerolog.New(sb).Hook(hook)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info().Ctx(ctx).Msg("msg")
+
+	m := unmarshal(t, sb.String())
+	if _, ok := m["foo"]; ok {
+		t.Error("fields should not have been added on info level:", m)
+	}
+
+	sb.Reset()
+	lg.Warn().Ctx(ctx).Msg("msg")
+
+	m = unmarshal(t, sb.String())
+	if m["foo"] != "bar" {
+		t.Error("could not find field in json", m)
+	}
+}
+
+func TestObject(t *testing.T) {
+	ctxerrIn := ctxerr.Instance{}
+	ctxerrIn.AddCreateHook(ctxerr.SetCodeHook)
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	err := ctxerrIn.NewHTTP(ctx, "code", "", 400, "msg")
+
+	sb := &strings.Builder{}
+	lg := zerolog.New(sb)
+	lg.Info().Object("error", ctxerrzerolog.Object(err)).Msg("msg")
+
+	m := unmarshal(t, sb.String())
+	e, ok := m["error"].(map[string]any)
+	if !ok {
+		t.Fatal("error was not an object", m)
+	}
+	if e[ctxerrzerolog.MessageKey] != "msg" {
+		t.Error("message did not match", e)
+	}
+	if e[ctxerrzerolog.CodeKey] != "code" {
+		t.Error("code did not match", e)
+	}
+	if e[ctxerrzerolog.StatusCodeKey] != float64(400) {
+		t.Error("status code did not match", e)
+	}
+	fields, ok := e[ctxerrzerolog.FieldsKey].(map[string]any)
+	if !ok || fields["foo"] != "bar" {
+		t.Error("fields did not match", e)
+	}
+	if _, ok := fields[ctxerr.FieldKeyCode]; ok {
+		t.Error("code should only be a key of the error object", fields)
+	}
+	if _, ok := fields[ctxerr.FieldKeyStatusCode]; ok {
+		t.Error("status code should only be a key of the error object", fields)
+	}
+}
+
+func TestErrorMarshalFunc(t *testing.T) {
+	orig := zerolog.ErrorMarshalFunc
+	zerolog.ErrorMarshalFunc = ctxerrzerolog.ErrorMarshalFunc
+	defer func() { zerolog.ErrorMarshalFunc = orig }()
+
+	sb := &strings.Builder{}
+	lg := zerolog.New(sb)
+
+	lg.Info().Err(errors.New("plain")).Msg("msg")
+	m := unmarshal(t, sb.String())
+	if m[zerolog.ErrorFieldName] != "plain" {
+		t.Error("go errors should be a string", m)
+	}
+
+	sb.Reset()
+	lg.Info().Err(ctxerr.New(context.Background(), "code", "msg")).Msg("msg")
+	m = unmarshal(t, sb.String())
+	if _, ok := m[zerolog.ErrorFieldName].(map[string]any); !ok {
+		t.Error("ctxerr errors should be an object", m)
+	}
+}
+
+func TestHandleHook(t *testing.T) {
+	sb := &strings.Builder{}
+	lg := zerolog.New(sb).Hook(ctxerrzerolog.NewContextHook())
+
+	in := ctxerr.NewInstance()
+	in.AddHandleHook(ctxerrzerolog.HandleHook(lg))
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	in.Handle(in.New(ctx, "code", "msg"))
+
+	m := unmarshal(t, sb.String())
+	if m["level"] != "error" {
+		t.Error("level should be error", m["level"])
+	}
+	if m["message"] != "msg" {
+		t.Error("message did not match", m)
+	}
+	e, ok := m[zerolog.ErrorFieldName].(map[string]any)
+	if !ok {
+		t.Fatal("error should be an object", m)
+	}
+	if fields, ok := e[ctxerrzerolog.FieldsKey].(map[string]any); !ok || fields["foo"] != "bar" {
+		t.Error("fields did not match", e)
+	}
+	if _, ok := m["foo"]; ok {
+		t.Error("field of the error should only be in the error object", m)
+	}
+}
+
+func ExampleNewContextHook() {
+	lg := zerolog.New(os.Stdout).Hook(ctxerrzerolog.NewContextHook())
+
+	ctx := ctxerr.SetField(context.Background(), "foo", "bar")
+	lg.Info().Ctx(ctx).Msg("msg")
+}